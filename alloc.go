@@ -0,0 +1,248 @@
+package flightrecorderreceiver
+
+import (
+	"encoding/binary"
+	"slices"
+	"time"
+
+	"golang.org/x/exp/trace"
+)
+
+// experimentAllocFree is the name of the trace experiment that is enabled
+// with GODEBUG=traceallocfree=1.
+const experimentAllocFree = "AllocFree"
+
+// Kinds of batches of the AllocFree experiment.
+const (
+	allocFreeTypesBatch = 0 // [{id, address, size, ptrspan, name length, name string} ...]
+	allocFreeInfoBatch  = 1 // [min heap addr, page size, min heap align, min stack align]
+)
+
+// Defaults of the runtime, used if a trace does not hold an info batch. The
+// runtime only writes it at the start of tracing.
+const (
+	defaultPageSize     = 8192
+	defaultMinHeapAlign = 8
+)
+
+// maxSmallSpanPages is the largest number of pages of a span holding objects
+// of a small size class.
+const maxSmallSpanPages = 10
+
+// sizeClassToSize maps a size class of the runtime to the size of its objects.
+// It is a copy of SizeClassToSize in internal/runtime/gc.
+var sizeClassToSize = [...]uint64{0, 8, 16, 24, 32, 48, 64, 80, 96, 112, 128, 144, 160, 176, 192, 208, 224, 240, 256, 288, 320, 352, 384, 416, 448, 480, 512, 576, 640, 704, 768, 896, 1024, 1152, 1280, 1408, 1536, 1792, 2048, 2304, 2688, 3072, 3200, 3456, 4096, 4864, 5376, 6144, 6528, 6784, 6912, 8192, 9472, 9728, 10240, 10880, 12288, 13568, 14336, 16384, 18432, 19072, 20480, 21760, 24576, 27264, 28672, 32768}
+
+// heapType is a type of heap objects.
+type heapType struct {
+	size uint64
+	name string
+}
+
+// heapSpan is a span of memory.
+type heapSpan struct {
+	npages uint64
+	// heap is set if the span holds heap objects.
+	heap      bool
+	sizeClass uint64
+}
+
+// heapObject is a heap object that exists within the trace.
+type heapObject struct {
+	size    uint64
+	linkIdx int32
+	attrs   []int32
+}
+
+// heapTracker turns the events of the AllocFree experiment into allocation
+// and in-use profiles.
+//
+// The runtime does not record stacks for these events, so samples have no
+// stack and are told apart by the type of their objects.
+type heapTracker struct {
+	lt        lookupTable
+	groups    *profileGroups
+	aggregate bool
+
+	pageSize     uint64
+	minHeapAlign uint64
+
+	// types maps type ID to the type. Type IDs are only valid within a
+	// single generation of the trace.
+	types map[uint64]heapType
+	// spans maps span ID to the span.
+	spans map[uint64]heapSpan
+	// objects maps object ID to objects that are still alive.
+	objects map[uint64]heapObject
+	// snapshot is set if the trace lists the objects that existed when
+	// tracing started. Otherwise objects allocated before are unknown.
+	snapshot bool
+}
+
+func newHeapTracker(lt lookupTable, groups *profileGroups, aggregate bool) *heapTracker {
+	return &heapTracker{
+		lt:           lt,
+		groups:       groups,
+		aggregate:    aggregate,
+		pageSize:     defaultPageSize,
+		minHeapAlign: defaultMinHeapAlign,
+		types:        make(map[uint64]heapType),
+		spans:        make(map[uint64]heapSpan),
+		objects:      make(map[uint64]heapObject),
+	}
+}
+
+// handleSync decodes the batches of the AllocFree experiment of the
+// generation that starts with s.
+func (h *heapTracker) handleSync(s trace.Sync) {
+	batches, ok := s.ExperimentalBatches[experimentAllocFree]
+	if !ok {
+		return
+	}
+	clear(h.types)
+	for _, batch := range batches {
+		if len(batch.Data) == 0 {
+			continue
+		}
+		switch batch.Data[0] {
+		case allocFreeTypesBatch:
+			h.decodeTypes(batch.Data[1:])
+		case allocFreeInfoBatch:
+			info, _, ok := readUvarints(batch.Data[1:], 3)
+			if !ok || info[1] == 0 || info[2] == 0 {
+				continue
+			}
+			h.pageSize = info[1]
+			h.minHeapAlign = info[2]
+		}
+	}
+}
+
+// decodeTypes decodes a batch of types.
+func (h *heapTracker) decodeTypes(data []byte) {
+	for len(data) > 0 {
+		// id, address, size, ptrspan, name length
+		header, rest, ok := readUvarints(data, 5)
+		if !ok {
+			return
+		}
+		data = rest
+		nameLen := header[4]
+		if uint64(len(data)) < nameLen {
+			return
+		}
+		h.types[header[0]] = heapType{
+			size: header[2],
+			name: string(data[:nameLen]),
+		}
+		data = data[nameLen:]
+	}
+}
+
+// readUvarints reads n varints from data and returns them along with the
+// remaining data.
+func readUvarints(data []byte, n int) ([]uint64, []byte, bool) {
+	values := make([]uint64, 0, n)
+	for range n {
+		v, size := binary.Uvarint(data)
+		if size <= 0 {
+			return nil, nil, false
+		}
+		values = append(values, v)
+		data = data[size:]
+	}
+	return values, data, true
+}
+
+// handleEvent handles an event of the AllocFree experiment. linkIdx and attrs
+// describe the goroutine the event happened on.
+func (h *heapTracker) handleEvent(e trace.ExperimentalEvent, ts time.Time, linkIdx int32, attrs []int32) {
+	arg := func(i int) uint64 {
+		if i >= len(e.Args) {
+			return 0
+		}
+		return e.ArgValue(i).Uint64()
+	}
+
+	switch e.Name {
+	case "Span", "SpanAlloc":
+		// Spans of heap objects carry their span class shifted by one, other
+		// spans have the lowest bit set. The span class holds the size class
+		// shifted by one.
+		span := heapSpan{npages: arg(1)}
+		if kindClass := arg(2); kindClass&1 == 0 {
+			span.heap = true
+			span.sizeClass = kindClass >> 2
+		}
+		h.spans[arg(0)] = span
+	case "SpanFree":
+		delete(h.spans, arg(0))
+	case "HeapObject":
+		// The runtime lists the objects that exist when tracing starts.
+		h.snapshot = true
+		id := arg(0)
+		h.objects[id] = h.newObject(id, arg(1), 0, nil)
+	case "HeapObjectAlloc":
+		id := arg(0)
+		obj := h.newObject(id, arg(1), linkIdx, attrs)
+		h.objects[id] = obj
+		h.addSample("alloc_objects", "count", obj, ts, 1)
+		h.addSample("alloc_space", "bytes", obj, ts, int64(obj.size))
+	case "HeapObjectFree":
+		delete(h.objects, arg(0))
+	}
+}
+
+// newObject returns the heap object with the given ID and type.
+func (h *heapTracker) newObject(id, typ uint64, linkIdx int32, attrs []int32) heapObject {
+	obj := heapObject{
+		size:    h.objectSize(id, typ),
+		linkIdx: linkIdx,
+		attrs:   attrs,
+	}
+	if t, ok := h.types[typ]; ok && t.name != "" {
+		obj.attrs = append(slices.Clip(obj.attrs), h.lt.AddKeyValueUnit(attrHeapObjectType, t.name, ""))
+	}
+	return obj
+}
+
+// objectSize returns the size of the heap slot of an object. It is derived
+// from the span holding the object and falls back to the size of its type.
+func (h *heapTracker) objectSize(id, typ uint64) uint64 {
+	objPage := id * h.minHeapAlign / h.pageSize
+	for i := uint64(0); i < maxSmallSpanPages && i <= objPage; i++ {
+		span, ok := h.spans[objPage-i]
+		if !ok || !span.heap || span.npages <= i {
+			continue
+		}
+		if span.sizeClass == 0 {
+			// Large objects occupy a span of their own.
+			return span.npages * h.pageSize
+		}
+		if span.sizeClass < uint64(len(sizeClassToSize)) {
+			return sizeClassToSize[span.sizeClass]
+		}
+	}
+	return h.types[typ].size
+}
+
+// addSample records value for obj at ts.
+func (h *heapTracker) addSample(sampleType, unit string, obj heapObject, ts time.Time, value int64) {
+	p := h.groups.get(sampleType, unit, nil, ts)
+	p.cover(ts, ts)
+	p.addSample(0, obj.linkIdx, obj.attrs, ts, value, h.aggregate)
+}
+
+// finish adds the objects that are still alive at end, the end of the trace, to
+// the in-use profiles. These are only complete if the trace lists the objects
+// that existed when tracing started, which a flight recorder usually does not.
+// The samples are taken at end, as they describe the heap at that time.
+func (h *heapTracker) finish(end time.Time) {
+	if !h.snapshot {
+		return
+	}
+	for _, obj := range h.objects {
+		h.addSample("inuse_objects", "count", obj, end, 1)
+		h.addSample("inuse_space", "bytes", obj, end, int64(obj.size))
+	}
+}
@@ -0,0 +1,175 @@
+package flightrecorderreceiver
+
+import (
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"golang.org/x/exp/trace"
+)
+
+// annotatedTask is a runtime/trace task.
+type annotatedTask struct {
+	typ string
+	// parent is nil if the task has no parent or its parent is unknown.
+	parent *annotatedTask
+	// creator is the goroutine the task was created on.
+	creator trace.GoID
+	// open is set from the begin of the task within the trace until its end.
+	// Tasks that began before the start of the trace are never open.
+	open bool
+
+	// span is the span of the task. It is empty if the task began before the
+	// start of the trace.
+	span ptrace.Span
+	// link holds the IDs of the distributed trace the task belongs to. It is
+	// empty if the task is not linked.
+	link link
+}
+
+// annotatedRegion is an active runtime/trace region.
+type annotatedRegion struct {
+	region trace.Region
+	span   ptrace.Span
+}
+
+// userAnnotations tracks the runtime/trace tasks and regions of each
+// goroutine. It is the single state that spans, links and the attributes of
+// samples are derived from.
+type userAnnotations struct {
+	// tasks maps task ID to the task. Tasks that began before the start of
+	// the trace are only known once they are referred to.
+	tasks map[trace.TaskID]*annotatedTask
+	// created maps goroutine ID to the open tasks created on it.
+	created map[trace.GoID][]trace.TaskID
+	// regions maps goroutine ID to its stack of active regions.
+	regions map[trace.GoID][]*annotatedRegion
+}
+
+func newUserAnnotations() *userAnnotations {
+	return &userAnnotations{
+		tasks:   make(map[trace.TaskID]*annotatedTask),
+		created: make(map[trace.GoID][]trace.TaskID),
+		regions: make(map[trace.GoID][]*annotatedRegion),
+	}
+}
+
+// lookupTask returns the task with the given ID, creating it if it is not
+// known yet.
+func (a *userAnnotations) lookupTask(id trace.TaskID) *annotatedTask {
+	task, ok := a.tasks[id]
+	if !ok {
+		task = &annotatedTask{creator: trace.NoGoroutine}
+		a.tasks[id] = task
+	}
+	return task
+}
+
+// beginTask handles EventTaskBegin and returns the task.
+func (a *userAnnotations) beginTask(ev trace.Event) *annotatedTask {
+	t := ev.Task()
+	goID := ev.Goroutine()
+	task := &annotatedTask{
+		typ:     t.Type,
+		parent:  a.tasks[t.Parent],
+		creator: goID,
+		open:    true,
+	}
+	a.tasks[t.ID] = task
+	a.created[goID] = append(a.created[goID], t.ID)
+	return task
+}
+
+// endTask handles EventTaskEnd. It returns the task, or nil if the task is not
+// open, e.g. because it began before the start of the trace.
+func (a *userAnnotations) endTask(ev trace.Event) *annotatedTask {
+	id := ev.Task().ID
+	task, ok := a.tasks[id]
+	if !ok || !task.open {
+		return nil
+	}
+	task.open = false
+	// Tasks can end on any goroutine, not just the one they were created on.
+	if i := slices.Index(a.created[task.creator], id); i >= 0 {
+		a.created[task.creator] = slices.Delete(a.created[task.creator], i, i+1)
+	}
+	return task
+}
+
+// beginRegion handles EventRegionBegin and returns the region.
+func (a *userAnnotations) beginRegion(ev trace.Event) *annotatedRegion {
+	goID := ev.Goroutine()
+	region := &annotatedRegion{region: ev.Region()}
+	a.regions[goID] = append(a.regions[goID], region)
+	return region
+}
+
+// endRegion handles EventRegionEnd. It returns the region, or nil if the region
+// began before the start of the trace.
+func (a *userAnnotations) endRegion(ev trace.Event) *annotatedRegion {
+	goID := ev.Goroutine()
+	active := a.regions[goID]
+	// Regions on a goroutine are strictly nested, so the most recent region
+	// with the same type is the one that ends.
+	for i := len(active) - 1; i >= 0; i-- {
+		if active[i].region.Type == ev.Region().Type {
+			a.regions[goID] = active[:i]
+			return active[i]
+		}
+	}
+	return nil
+}
+
+// activeTask returns the task the goroutine is currently working on. That is
+// the task of its innermost region or otherwise the most recent open task
+// created on the goroutine.
+func (a *userAnnotations) activeTask(goID trace.GoID) trace.TaskID {
+	if active := a.regions[goID]; len(active) > 0 {
+		if task := active[len(active)-1].region.Task; task != trace.BackgroundTask {
+			return task
+		}
+	}
+	if tasks := a.created[goID]; len(tasks) > 0 {
+		return tasks[len(tasks)-1]
+	}
+	return trace.BackgroundTask
+}
+
+// activeSpan returns the span an event of the given goroutine and task
+// happened in. That is the innermost active region of the goroutine if it
+// belongs to the task, otherwise the span of the task if it is open. It returns
+// an empty span if there is none.
+func (a *userAnnotations) activeSpan(goID trace.GoID, taskID trace.TaskID) ptrace.Span {
+	if active := a.regions[goID]; len(active) > 0 {
+		if region := active[len(active)-1]; region.region.Task == taskID {
+			return region.span
+		}
+	}
+	if task, ok := a.tasks[taskID]; ok && task.open {
+		return task.span
+	}
+	return ptrace.Span{}
+}
+
+// appendAttributes appends the innermost region and the task of the goroutine
+// to attrs. If regionPath is set, the path of all active regions of the
+// goroutine is appended as well.
+func (a *userAnnotations) appendAttributes(lt lookupTable, attrs []int32, goID trace.GoID, regionPath bool) []int32 {
+	if active := a.regions[goID]; len(active) > 0 {
+		attrs = append(attrs, lt.AddKeyValueUnit(attrRegion, active[len(active)-1].region.Type, ""))
+		if regionPath {
+			path := make([]string, 0, len(active))
+			for _, region := range active {
+				path = append(path, region.region.Type)
+			}
+			attrs = append(attrs, lt.AddKeyValueUnit(attrRegionPath, strings.Join(path, "/"), ""))
+		}
+	}
+	if id := a.activeTask(goID); id != trace.BackgroundTask {
+		attrs = append(attrs, lt.AddKeyIntValueUnit(attrTaskID, int64(id), ""))
+		if task, ok := a.tasks[id]; ok && task.typ != "" {
+			attrs = append(attrs, lt.AddKeyValueUnit(attrTaskType, task.typ, ""))
+		}
+	}
+	return attrs
+}
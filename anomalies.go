@@ -0,0 +1,214 @@
+package flightrecorderreceiver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"golang.org/x/exp/trace"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+)
+
+// Event names of the log records of anomalies.
+const (
+	anomalySTWPause     = "go.anomaly.stw_pause"
+	anomalySchedLatency = "go.anomaly.sched_latency"
+	anomalyMutexBlock   = "go.anomaly.mutex_block"
+)
+
+// mutexBlockReason is the reason of goroutines waiting on sync.Mutex,
+// sync.RWMutex and other primitives of package sync. Waits on a mutex are told
+// apart by the innermost frame of their stack, see isMutexWait.
+const mutexBlockReason = "sync"
+
+// mutexFuncPrefixes are the prefixes of the functions goroutines block on a
+// mutex in.
+var mutexFuncPrefixes = []string{"sync.(*Mutex).", "sync.(*RWMutex)."}
+
+// anomalyStackFrames is the number of innermost frames in the stack summary of
+// an anomaly.
+const anomalyStackFrames = 5
+
+// anomalyWait is a wait of a goroutine that may become an anomaly.
+type anomalyWait struct {
+	goID  trace.GoID
+	since time.Time
+	stack trace.Stack
+}
+
+// anomalyDetector emits a log record whenever a stop-the-world pause, the
+// time a goroutine waits to be scheduled or the time a goroutine is blocked
+// on a mutex exceeds its threshold.
+type anomalyDetector struct {
+	cfg     AnomaliesConfig
+	records plog.LogRecordSlice
+	history sourceHistory
+
+	// stw maps active stop-the-world ranges to their start.
+	stw map[gcRangeKey]anomalyWait
+	// runnable maps runnable goroutines to the start of their wait.
+	runnable map[trace.GoID]anomalyWait
+	// blocked maps goroutines blocked on a mutex to the start of their wait.
+	blocked map[trace.GoID]anomalyWait
+	// stacks maps goroutine ID to its most recent stack.
+	stacks map[trace.GoID]trace.Stack
+}
+
+func newAnomalyDetector(cfg AnomaliesConfig, records plog.LogRecordSlice, history sourceHistory) *anomalyDetector {
+	return &anomalyDetector{
+		cfg:      cfg,
+		records:  records,
+		history:  history,
+		stw:      make(map[gcRangeKey]anomalyWait),
+		runnable: make(map[trace.GoID]anomalyWait),
+		blocked:  make(map[trace.GoID]anomalyWait),
+		stacks:   make(map[trace.GoID]trace.Stack),
+	}
+}
+
+// handleRange handles EventRangeBegin and EventRangeEnd of stop-the-world
+// pauses at ts.
+func (d *anomalyDetector) handleRange(ev trace.Event, ts time.Time) {
+	if d.cfg.STWPause <= 0 {
+		return
+	}
+	r := ev.Range()
+	reason, ok := stwReason(r.Name)
+	if !ok {
+		return
+	}
+	key := gcRangeKey{name: r.Name, scope: r.Scope}
+	switch ev.Kind() {
+	case trace.EventRangeBegin:
+		d.stw[key] = anomalyWait{goID: ev.Goroutine(), since: ts, stack: ev.Stack()}
+	case trace.EventRangeEnd:
+		w, ok := d.stw[key]
+		if !ok {
+			return
+		}
+		delete(d.stw, key)
+		if ts.Sub(w.since) < d.cfg.STWPause || d.reported(anomalySTWPause, w, ts, d.cfg.STWPause) {
+			return
+		}
+		record := d.report(anomalySTWPause, w, ts, d.cfg.STWPause,
+			fmt.Sprintf("stop-the-world pause (%s) of %s exceeded %s", reason, ts.Sub(w.since), d.cfg.STWPause))
+		record.Attributes().PutStr(attrSTWReason, reason)
+	}
+}
+
+// handleTransition handles a state transition of a goroutine at ts.
+func (d *anomalyDetector) handleTransition(st trace.StateTransition, ts time.Time) {
+	goID := st.Resource.Goroutine()
+	if hasFrames(st.Stack) {
+		d.stacks[goID] = st.Stack
+	}
+	from, to := st.Goroutine()
+	if from == to {
+		// The trace restates the state of goroutines in every generation.
+		return
+	}
+
+	if w, ok := d.runnable[goID]; ok {
+		delete(d.runnable, goID)
+		if to == trace.GoRunning && ts.Sub(w.since) >= d.cfg.SchedLatency && !d.reported(anomalySchedLatency, w, ts, d.cfg.SchedLatency) {
+			d.report(anomalySchedLatency, w, ts, d.cfg.SchedLatency,
+				fmt.Sprintf("goroutine %d waited %s to be scheduled, exceeding %s", goID, ts.Sub(w.since), d.cfg.SchedLatency))
+		}
+	}
+	if w, ok := d.blocked[goID]; ok {
+		delete(d.blocked, goID)
+		if ts.Sub(w.since) >= d.cfg.MutexBlock && !d.reported(anomalyMutexBlock, w, ts, d.cfg.MutexBlock) {
+			d.report(anomalyMutexBlock, w, ts, d.cfg.MutexBlock,
+				fmt.Sprintf("goroutine %d was blocked on a mutex for %s, exceeding %s", goID, ts.Sub(w.since), d.cfg.MutexBlock))
+		}
+	}
+
+	w := anomalyWait{goID: goID, since: ts, stack: d.stacks[goID]}
+	switch {
+	case to == trace.GoRunnable && d.cfg.SchedLatency > 0:
+		d.runnable[goID] = w
+	case to == trace.GoWaiting && st.Reason == mutexBlockReason && d.cfg.MutexBlock > 0 && isMutexWait(st.Stack):
+		d.blocked[goID] = w
+	}
+}
+
+// finish reports goroutines that are still blocked on a mutex at end, e.g.
+// because of a deadlock.
+func (d *anomalyDetector) finish(end time.Time) {
+	for goID, w := range d.blocked {
+		if end.Sub(w.since) >= d.cfg.MutexBlock && !d.reported(anomalyMutexBlock, w, end, d.cfg.MutexBlock) {
+			d.report(anomalyMutexBlock, w, end, d.cfg.MutexBlock,
+				fmt.Sprintf("goroutine %d was still blocked on a mutex after %s, exceeding %s", goID, end.Sub(w.since), d.cfg.MutexBlock))
+		}
+	}
+	clear(d.blocked)
+}
+
+// reported reports whether the anomaly of w until end was reported for the
+// previous flight record of the source.
+func (d *anomalyDetector) reported(name string, w anomalyWait, end time.Time, threshold time.Duration) bool {
+	if d.history.emitted(end) {
+		return true
+	}
+	// Goroutines that were still blocked on a mutex at the end of the
+	// previous flight record were reported with it.
+	return name == anomalyMutexBlock && d.history.emitted(w.since.Add(threshold))
+}
+
+// report appends the log record of an anomaly covering [w.since, end].
+func (d *anomalyDetector) report(name string, w anomalyWait, end time.Time, threshold time.Duration, message string) plog.LogRecord {
+	record := d.records.AppendEmpty()
+	record.SetTimestamp(pcommon.NewTimestampFromTime(w.since))
+	record.SetEventName(name)
+	record.SetSeverityNumber(plog.SeverityNumberWarn)
+	record.SetSeverityText(plog.SeverityNumberWarn.String())
+	record.Body().SetStr(message)
+	record.Attributes().PutStr(attrAnomalyWindowStart, w.since.Format(time.RFC3339Nano))
+	record.Attributes().PutStr(attrAnomalyWindowEnd, end.Format(time.RFC3339Nano))
+	record.Attributes().PutDouble(attrAnomalyDuration, end.Sub(w.since).Seconds())
+	record.Attributes().PutDouble(attrAnomalyThreshold, threshold.Seconds())
+	if w.goID != trace.NoGoroutine {
+		record.Attributes().PutInt(attrGoID, int64(w.goID))
+	}
+	if summary := stackSummary(w.stack); summary != "" {
+		record.Attributes().PutStr(string(semconv.CodeStacktraceKey), summary)
+	}
+	return record
+}
+
+// isMutexWait reports whether a goroutine that waits with the reason
+// mutexBlockReason and the given stack is blocked on a mutex rather than on
+// e.g. a sync.WaitGroup. Frames of the runtime and of internal/sync, which
+// implements sync.Mutex, are skipped.
+func isMutexWait(stack trace.Stack) bool {
+	for frame := range stack.Frames() {
+		if strings.HasPrefix(frame.Func, "runtime.") || strings.HasPrefix(frame.Func, "internal/sync.") {
+			continue
+		}
+		for _, prefix := range mutexFuncPrefixes {
+			if strings.HasPrefix(frame.Func, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// stackSummary formats the innermost frames of stack, one per line.
+func stackSummary(stack trace.Stack) string {
+	var b strings.Builder
+	n := 0
+	for frame := range stack.Frames() {
+		if n == anomalyStackFrames {
+			b.WriteString("...\n")
+			break
+		}
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Func, frame.File, frame.Line)
+		n++
+	}
+	return b.String()
+}
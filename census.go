@@ -0,0 +1,95 @@
+package flightrecorderreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"golang.org/x/exp/trace"
+
+	"github.com/florianl/flightrecorderreceiver/internal/metadata"
+)
+
+// censusStates are the goroutine states that are counted, along with the
+// value of their attribute.
+var censusStates = []struct {
+	state trace.GoState
+	attr  metadata.AttributeGoGoroutineState
+}{
+	{trace.GoRunning, metadata.AttributeGoGoroutineStateRunning},
+	{trace.GoRunnable, metadata.AttributeGoGoroutineStateRunnable},
+	{trace.GoWaiting, metadata.AttributeGoGoroutineStateWaiting},
+	{trace.GoSyscall, metadata.AttributeGoGoroutineStateSyscall},
+}
+
+// censusSample is the number of goroutines in a state at a point in time.
+type censusSample struct {
+	ts    pcommon.Timestamp
+	state metadata.AttributeGoGoroutineState
+	count int64
+}
+
+// goroutineCensus counts the goroutines in each state and samples the counts
+// at a fixed resolution. It is disabled if the resolution is not positive.
+type goroutineCensus struct {
+	resolution time.Duration
+	counts     map[trace.GoState]int64
+	// next is the time of the next sample. It is zero until the first
+	// transition.
+	next time.Time
+
+	samples []censusSample
+}
+
+func newGoroutineCensus(resolution time.Duration) *goroutineCensus {
+	return &goroutineCensus{
+		resolution: resolution,
+		counts:     make(map[trace.GoState]int64),
+	}
+}
+
+// handleTransition samples the counts until ts and applies a state transition
+// of a goroutine at ts.
+func (c *goroutineCensus) handleTransition(st trace.StateTransition, ts time.Time) {
+	if c.resolution <= 0 {
+		return
+	}
+	c.sampleUntil(ts)
+	from, to := st.Goroutine()
+	// Goroutines enter the trace from an undetermined state.
+	if from != trace.GoUndetermined {
+		c.counts[from]--
+	}
+	c.counts[to]++
+}
+
+// sampleUntil samples the counts at all sample times before ts. Sample times
+// are aligned to multiples of the resolution, so samples of many traces line
+// up.
+func (c *goroutineCensus) sampleUntil(ts time.Time) {
+	if c.next.IsZero() {
+		c.next = time.Unix(0, ts.UnixNano()-ts.UnixNano()%c.resolution.Nanoseconds())
+		if c.next.Before(ts) {
+			c.next = c.next.Add(c.resolution)
+		}
+	}
+	for c.next.Before(ts) {
+		sampleTS := pcommon.NewTimestampFromTime(c.next)
+		for _, s := range censusStates {
+			c.samples = append(c.samples, censusSample{ts: sampleTS, state: s.attr, count: c.counts[s.state]})
+		}
+		c.next = c.next.Add(c.resolution)
+	}
+}
+
+// recordMetrics samples the counts until end and records them in mb.
+func (c *goroutineCensus) recordMetrics(mb *metadata.MetricsBuilder, end time.Time) {
+	if c.resolution <= 0 || c.next.IsZero() {
+		return
+	}
+	c.sampleUntil(end.Add(1))
+
+	for _, s := range c.samples {
+		mb.RecordGoGoroutineStateCountDataPoint(s.ts, s.count, s.state)
+	}
+	c.samples = nil
+}
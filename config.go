@@ -1,10 +1,15 @@
 package flightrecorderreceiver
 
 import (
+	"errors"
+	"fmt"
 	"sync"
+	"time"
 
-	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/scraper/scraperhelper"
+
+	"github.com/florianl/flightrecorderreceiver/internal/metadata"
 )
 
 type Config struct {
@@ -13,19 +18,190 @@ type Config struct {
 	// Include specifies the glob pattern for flight recorder files to process
 	Include string `mapstructure:"include"`
 
+	// AggregateSamples merges samples with identical stack and attributes
+	// within a profile into a single sample with many timestamps. Without it,
+	// every event is a sample of its own, e.g. for timeline views.
+	AggregateSamples bool `mapstructure:"aggregate_samples"`
+
+	// ExcludeGCWorkers drops samples of GC mark worker goroutines from the
+	// wall profile.
+	ExcludeGCWorkers bool `mapstructure:"exclude_gc_workers"`
+
+	// GroupBy defines how samples are grouped into profiles. Supported values
+	// are "goroutine", "range_name", "file" and "window".
+	GroupBy string `mapstructure:"group_by"`
+
+	// RegionPath attaches the path of all active regions of a goroutine to its
+	// samples, in addition to the innermost region.
+	RegionPath bool `mapstructure:"region_path"`
+
+	// Window is the duration of a single profile if GroupBy is "window".
+	Window time.Duration `mapstructure:"window"`
+
+	// MMUWindows are the window sizes the minimum mutator utilization is
+	// computed for.
+	MMUWindows []time.Duration `mapstructure:"mmu_windows"`
+
+	// CensusResolution is the interval at which the number of goroutines in
+	// each state is sampled. Every interval adds a data point per state, so
+	// fine resolutions add up quickly over long flight records. The census is
+	// disabled if it is zero.
+	CensusResolution time.Duration `mapstructure:"census_resolution"`
+
+	// SemconvMetricNames renames runtime/metrics that have an equivalent in
+	// the OTel semantic conventions for Go runtime metrics, e.g.
+	// /gc/heap/goal:bytes to go.memory.gc.goal, and emits them with the type
+	// the semantic conventions define. It also adds go.goroutine.count.
+	SemconvMetricNames bool `mapstructure:"semconv_metric_names"`
+
+	// Links configures how samples are linked to distributed traces.
+	Links LinksConfig `mapstructure:"links"`
+
+	// MetricsResolution is the size of the windows the data points of
+	// runtime/metrics metrics are aggregated in. Every data point is kept if
+	// it is zero.
+	MetricsResolution time.Duration `mapstructure:"metrics_resolution"`
+
+	// MetricsAggregation defines how the data points of a window are
+	// aggregated. Supported values are "last", "min", "max" and "avg".
+	MetricsAggregation string `mapstructure:"metrics_aggregation"`
+
+	// MetricsSummary aggregates all data points of a runtime/metrics metric
+	// in a flight record into a single data point.
+	MetricsSummary bool `mapstructure:"metrics_summary"`
+
+	// RuntimeMetrics filters the runtime/metrics metrics of the trace.
+	RuntimeMetrics RuntimeMetricsConfig `mapstructure:"runtime_metrics"`
+
+	// Deduplication drops data of a flight record that was already emitted
+	// for an earlier flight record of the same source.
+	Deduplication DeduplicationConfig `mapstructure:"deduplication"`
+
+	// Anomalies configures the thresholds above which a log record is emitted
+	// for a stall within a flight record.
+	Anomalies AnomaliesConfig `mapstructure:"anomalies"`
+
+	// MetricsBuilderConfig enables or disables the metrics derived from the
+	// trace.
+	metadata.MetricsBuilderConfig `mapstructure:",squash"`
+
+	// Histograms enables or disables the histograms derived from the trace.
+	Histograms HistogramsConfig `mapstructure:"histograms"`
+
 	// Internal fields for receiver coordination (not exposed via config)
 	receiver     *flightRecorderReceiver
+	receiverErr  error
 	receiverOnce sync.Once
 
 	// prevent unkeyed literal initialization
 	_ struct{}
 }
 
+// LinksConfig configures the extraction of W3C trace and span IDs from the
+// names and log messages of runtime/trace tasks.
+type LinksConfig struct {
+	// Extractor defines how IDs are found. Supported values are "key_value"
+	// and "regex". Links are disabled if it is empty.
+	Extractor string `mapstructure:"extractor"`
+
+	// Pattern is the regular expression of the "regex" extractor. The IDs are
+	// taken from its groups named "trace_id" and "span_id".
+	Pattern string `mapstructure:"pattern"`
+}
+
+// RuntimeMetricsConfig selects runtime/metrics metrics by regular expressions
+// matched against their names in the trace, e.g. "/gc/heap/goal:bytes".
+type RuntimeMetricsConfig struct {
+	// Include keeps only metrics that match one of the patterns. All metrics
+	// are kept if it is empty.
+	Include []string `mapstructure:"include"`
+
+	// Exclude drops metrics that match one of the patterns.
+	Exclude []string `mapstructure:"exclude"`
+}
+
+// DeduplicationConfig configures the deduplication of overlapping flight
+// records.
+type DeduplicationConfig struct {
+	// Enabled drops metric data points and profile samples that are not
+	// newer than those emitted before for the same source.
+	Enabled bool `mapstructure:"enabled"`
+
+	// SourcePattern is a regular expression that identifies the source of a
+	// flight record by its path, e.g. the process that wrote it. The source
+	// is its first group, or its whole match if it has no groups. The path
+	// identifies the source if it is empty or does not match.
+	SourcePattern string `mapstructure:"source_pattern"`
+}
+
+// AnomaliesConfig holds the thresholds of anomalies. An anomaly is not
+// reported if its threshold is zero.
+type AnomaliesConfig struct {
+	// STWPause is the threshold of stop-the-world pauses.
+	STWPause time.Duration `mapstructure:"stw_pause"`
+
+	// SchedLatency is the threshold of the time a runnable goroutine waits
+	// to be scheduled.
+	SchedLatency time.Duration `mapstructure:"sched_latency"`
+
+	// MutexBlock is the threshold of the time a single goroutine is blocked
+	// on a sync.Mutex or sync.RWMutex.
+	MutexBlock time.Duration `mapstructure:"mutex_block"`
+}
+
+// HistogramsConfig enables or disables the histograms derived from the trace
+// in the same way as the metrics of MetricsBuilderConfig.
+type HistogramsConfig struct {
+	GoGcStwDuration metadata.MetricConfig `mapstructure:"go.gc.stw.duration"`
+}
+
+// Validate checks if the receiver configuration is valid.
+func (c *Config) Validate() error {
+	switch c.GroupBy {
+	case groupByGoroutine, groupByRangeName, groupByFile:
+	case groupByWindow:
+		if c.Window <= 0 {
+			return errors.New("window must be positive when grouping profiles by window")
+		}
+	default:
+		return fmt.Errorf("unsupported group_by %q", c.GroupBy)
+	}
+	for _, window := range c.MMUWindows {
+		if window <= 0 {
+			return fmt.Errorf("mmu_windows must be positive, got %s", window)
+		}
+	}
+	if c.CensusResolution < 0 {
+		return errors.New("census_resolution must not be negative")
+	}
+	if c.MetricsResolution < 0 {
+		return errors.New("metrics_resolution must not be negative")
+	}
+	switch c.MetricsAggregation {
+	case metricsAggregationLast, metricsAggregationMin, metricsAggregationMax, metricsAggregationAvg:
+	default:
+		return fmt.Errorf("unsupported metrics_aggregation %q", c.MetricsAggregation)
+	}
+	if _, err := linkPattern(c.Links); err != nil {
+		return err
+	}
+	if _, err := newRuntimeMetricsFilter(c.RuntimeMetrics); err != nil {
+		return err
+	}
+	if _, err := sourcePattern(c.Deduplication); err != nil {
+		return err
+	}
+	if c.Anomalies.STWPause < 0 || c.Anomalies.SchedLatency < 0 || c.Anomalies.MutexBlock < 0 {
+		return errors.New("anomalies thresholds must not be negative")
+	}
+	return nil
+}
+
 // getOrCreateReceiver returns the receiver instance, creating it if necessary.
-// This ensures that both profiles and metrics pipelines share the same receiver.
-func (c *Config) getOrCreateReceiver(settings component.TelemetrySettings) *flightRecorderReceiver {
+// This ensures that the profiles, metrics, traces and logs pipelines share the same receiver.
+func (c *Config) getOrCreateReceiver(settings receiver.Settings) (*flightRecorderReceiver, error) {
 	c.receiverOnce.Do(func() {
-		c.receiver = newFlightRecorderReceiver(c, settings)
+		c.receiver, c.receiverErr = newFlightRecorderReceiver(c, settings)
 	})
-	return c.receiver
+	return c.receiver, c.receiverErr
 }
@@ -0,0 +1,190 @@
+package flightrecorderreceiver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *Config)
+		wantErr bool
+	}{
+		{
+			name:   "default",
+			modify: func(*Config) {},
+		},
+		{
+			name: "group by window",
+			modify: func(cfg *Config) {
+				cfg.GroupBy = groupByWindow
+			},
+		},
+		{
+			name: "group by window without window",
+			modify: func(cfg *Config) {
+				cfg.GroupBy = groupByWindow
+				cfg.Window = 0
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported group by",
+			modify: func(cfg *Config) {
+				cfg.GroupBy = "thread"
+			},
+			wantErr: true,
+		},
+		{
+			name: "non-positive mmu window",
+			modify: func(cfg *Config) {
+				cfg.MMUWindows = []time.Duration{time.Millisecond, 0}
+			},
+			wantErr: true,
+		},
+		{
+			name: "census disabled",
+			modify: func(cfg *Config) {
+				cfg.CensusResolution = 0
+			},
+		},
+		{
+			name: "negative census resolution",
+			modify: func(cfg *Config) {
+				cfg.CensusResolution = -time.Millisecond
+			},
+			wantErr: true,
+		},
+		{
+			name: "links key_value",
+			modify: func(cfg *Config) {
+				cfg.Links.Extractor = linkExtractorKeyValue
+			},
+		},
+		{
+			name: "links regex",
+			modify: func(cfg *Config) {
+				cfg.Links = LinksConfig{
+					Extractor: linkExtractorRegex,
+					Pattern:   `traceparent=00-(?P<trace_id>[0-9a-f]{32})-(?P<span_id>[0-9a-f]{16})`,
+				}
+			},
+		},
+		{
+			name: "links regex without trace_id group",
+			modify: func(cfg *Config) {
+				cfg.Links = LinksConfig{
+					Extractor: linkExtractorRegex,
+					Pattern:   `span_id=(?P<span_id>[0-9a-f]{16})`,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "links invalid regex",
+			modify: func(cfg *Config) {
+				cfg.Links = LinksConfig{
+					Extractor: linkExtractorRegex,
+					Pattern:   `(`,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported links extractor",
+			modify: func(cfg *Config) {
+				cfg.Links.Extractor = "baggage"
+			},
+			wantErr: true,
+		},
+		{
+			name: "metrics resolution",
+			modify: func(cfg *Config) {
+				cfg.MetricsResolution = time.Second
+				cfg.MetricsAggregation = metricsAggregationAvg
+			},
+		},
+		{
+			name: "negative metrics resolution",
+			modify: func(cfg *Config) {
+				cfg.MetricsResolution = -time.Second
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported metrics aggregation",
+			modify: func(cfg *Config) {
+				cfg.MetricsAggregation = "median"
+			},
+			wantErr: true,
+		},
+		{
+			name: "deduplication source pattern",
+			modify: func(cfg *Config) {
+				cfg.Deduplication.SourcePattern = `app-(\d+)-`
+			},
+		},
+		{
+			name: "deduplication invalid source pattern",
+			modify: func(cfg *Config) {
+				cfg.Deduplication.SourcePattern = `(`
+			},
+			wantErr: true,
+		},
+		{
+			name: "anomalies",
+			modify: func(cfg *Config) {
+				cfg.Anomalies = AnomaliesConfig{
+					STWPause:     10 * time.Millisecond,
+					SchedLatency: 10 * time.Millisecond,
+					MutexBlock:   100 * time.Millisecond,
+				}
+			},
+		},
+		{
+			name: "negative anomaly threshold",
+			modify: func(cfg *Config) {
+				cfg.Anomalies.MutexBlock = -time.Millisecond
+			},
+			wantErr: true,
+		},
+		{
+			name: "runtime metrics filter",
+			modify: func(cfg *Config) {
+				cfg.RuntimeMetrics = RuntimeMetricsConfig{
+					Include: []string{`^/gc/`},
+					Exclude: []string{`:bytes$`},
+				}
+			},
+		},
+		{
+			name: "runtime metrics invalid include",
+			modify: func(cfg *Config) {
+				cfg.RuntimeMetrics.Include = []string{`(`}
+			},
+			wantErr: true,
+		},
+		{
+			name: "runtime metrics invalid exclude",
+			modify: func(cfg *Config) {
+				cfg.RuntimeMetrics.Exclude = []string{`[`}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantErr && err == nil {
+				t.Fatal("expected an error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -4,17 +4,48 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/pprofile"
-	"go.uber.org/zap"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver"
 	"golang.org/x/exp/trace"
 
 	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+
+	"github.com/florianl/flightrecorderreceiver/internal/metadata"
+)
+
+// Attribute keys that are not part of OTel SemConv.
+const (
+	attrGoID                   = "GoID"
+	attrGoroutineStartFunction = "go.goroutine.start_function"
+	attrGoroutineStateFrom     = "go.goroutine.state.from"
+	attrGoroutineStateTo       = "go.goroutine.state.to"
+	attrGoroutineStateReason   = "go.goroutine.state.reason"
+	attrProcID                 = "go.proc.id"
+	attrTaskID                 = "go.trace.task.id"
+	attrLogCategory            = "go.trace.log.category"
+	attrTaskType               = "go.trace.task.type"
+	attrRegion                 = "go.trace.region"
+	attrRegionPath             = "go.trace.region.path"
+	attrGoroutineLabel         = "go.goroutine.label"
+	attrHeapObjectType         = "go.heap.object.type"
+	attrRange                  = "go.trace.range"
+	attrSTWReason              = "go.gc.stw.reason"
+	attrProfileID              = "profile.id"
+	attrProfileType            = "profile.type"
+	attrAnomalyWindowStart     = "go.anomaly.window.start"
+	attrAnomalyWindowEnd       = "go.anomaly.window.end"
+	attrAnomalyDuration        = "go.anomaly.duration"
+	attrAnomalyThreshold       = "go.anomaly.threshold"
 )
 
 // extractMetricNameUnit extracts the name and unit from a flight recorder metric name.
@@ -25,12 +56,29 @@ func extractMetricNameUnit(traceName string) (name, unit string) {
 	return name, unit
 }
 
+// signals holds the OpenTelemetry data converted from a single Flight
+// Recorder trace.
+type signals struct {
+	profiles pprofile.Profiles
+	metrics  pmetric.Metrics
+	traces   ptrace.Traces
+	logs     plog.Logs
+	// start and end are the wall times of the first and the last event.
+	// They are zero if the trace has no clock snapshot.
+	start time.Time
+	end   time.Time
+}
+
 // convert converts a Flight Recorder trace from the provided reader into
-// both OpenTelemetry Profiles and Metrics data structures.
-func convert(ctx context.Context, logger *zap.Logger, f io.Reader) (pprofile.Profiles, pmetric.Metrics, error) {
+// OpenTelemetry Profiles, Metrics, Traces and Logs data structures. Spans, log
+// records and counts of derived delta sums that were already emitted for an
+// earlier flight record of the same source, according to history, are left
+// out.
+func convert(ctx context.Context, set receiver.Settings, cfg *Config, f io.Reader, history sourceHistory) (signals, error) {
+	logger := set.Logger
 	r, err := trace.NewReader(f)
 	if err != nil {
-		return pprofile.Profiles{}, pmetric.Metrics{}, err
+		return signals{}, err
 	}
 	lt := createLookupTable()
 
@@ -49,18 +97,57 @@ func convert(ctx context.Context, logger *zap.Logger, f io.Reader) (pprofile.Pro
 	currentScopeMetric.SetSchemaUrl(semconv.SchemaURL)
 	metricsMap := make(map[string]pmetric.Metric)
 
+	traces := ptrace.NewTraces()
+	rsSlice := traces.ResourceSpans()
+	currentResourceSpans := rsSlice.AppendEmpty()
+	currentResourceSpans.SetSchemaUrl(semconv.SchemaURL)
+	currentScopeSpans := currentResourceSpans.ScopeSpans().AppendEmpty()
+	currentScopeSpans.SetSchemaUrl(semconv.SchemaURL)
+
+	logs := plog.NewLogs()
+	rlSlice := logs.ResourceLogs()
+	currentResourceLogs := rlSlice.AppendEmpty()
+	currentResourceLogs.SetSchemaUrl(semconv.SchemaURL)
+	currentScopeLogs := currentResourceLogs.ScopeLogs().AppendEmpty()
+	currentScopeLogs.SetSchemaUrl(semconv.SchemaURL)
+
 	// most recent clock information from sync events
 	var clockSnap *trace.ClockSnapshot
-
-	// rangeState tracks an in-progress range for a single goroutine.
-	type rangeState struct {
-		startTS     time.Time
-		lastEventTS time.Time
-		profile     pprofile.Profile
-		initialized bool // true once at least one sample has been added
-	}
-	// activeRanges maps goroutine ID to its current range state.
+	// wall time of the first and most recent event after clock synchronization
+	var firstTS, lastTS time.Time
+
+	groups := newProfileGroups(lt, spSlice, cfg)
+	// ranges holds the ranges that are active at the current event.
+	ranges := &openRanges{}
+	// activeRanges maps goroutine ID to the state of its samples within the
+	// range it is currently attributed to.
 	activeRanges := make(map[trace.GoID]*rangeState)
+	annotations := newUserAnnotations()
+	spans := newSpanConverter(currentScopeSpans.Spans(), annotations, history)
+	// startFunctions maps goroutine ID to the function it was started with.
+	startFunctions := make(map[trace.GoID]string)
+	// labels maps goroutine ID to its most recent label.
+	labels := make(map[trace.GoID]string)
+	heap := newHeapTracker(lt, groups, cfg.AggregateSamples)
+	gc := newGCStats(history)
+	goroutines := newGoroutineSummary(history)
+	census := newGoroutineCensus(cfg.CensusResolution)
+	quality := newTraceQuality(history)
+	heapTrajectory := newHeapTrajectory()
+	threads := newThreadStats()
+	goroutineCount := &goroutineCount{}
+	anomalies := newAnomalyDetector(cfg.Anomalies, currentScopeLogs.LogRecords(), history)
+	utilization := newProcUtilization(func(goID trace.GoID) bool {
+		return isGCWorker(startFunctions[goID], labels[goID])
+	})
+	links, err := newTaskLinks(lt, cfg.Links, annotations)
+	if err != nil {
+		return signals{}, err
+	}
+	runtimeMetricsFilter, err := newRuntimeMetricsFilter(cfg.RuntimeMetrics)
+	if err != nil {
+		return signals{}, err
+	}
 
 eventLoop:
 	for {
@@ -75,7 +162,18 @@ eventLoop:
 			if err == io.EOF {
 				break
 			}
-			return pprofile.Profiles{}, pmetric.Metrics{}, err
+			return signals{}, err
+		}
+		threads.handleEvent(ev)
+		if clockSnap != nil {
+			lastTS = eventWallTime(ev.Time(), clockSnap)
+			if firstTS.IsZero() {
+				firstTS = lastTS
+			}
+			finishPendingSamples(activeRanges, ev, lastTS)
+			quality.count(lastTS)
+		} else {
+			quality.count(time.Time{})
 		}
 		switch ev.Kind() {
 		case trace.EventSync:
@@ -83,16 +181,23 @@ eventLoop:
 			if s.ClockSnapshot != nil {
 				clockSnap = s.ClockSnapshot
 			}
+			heap.handleSync(s)
 			continue eventLoop
 		case trace.EventMetric:
 			// Extract metrics from the event
 			if clockSnap == nil {
 				logger.Error("received EventMetric before clock synchronization")
+				quality.drop(metadata.AttributeFlightrecorderDropReasonBeforeClockSync, ev.Kind())
 				continue eventLoop
 			}
 
 			m := ev.Metric()
-			metricName, metricUnit := extractMetricNameUnit(m.Name)
+			utilization.handleMetric(m, eventWallTime(ev.Time(), clockSnap))
+			heapTrajectory.handleMetric(m, eventWallTime(ev.Time(), clockSnap))
+			if !runtimeMetricsFilter.match(m.Name) {
+				continue eventLoop
+			}
+			metricName, metricUnit, metricDescription := metricInfo(m.Name, cfg.SemconvMetricNames)
 
 			// Get or create metric
 			metric, exists := metricsMap[metricName]
@@ -100,149 +205,334 @@ eventLoop:
 				metric = currentScopeMetric.Metrics().AppendEmpty()
 				metric.SetName(metricName)
 				metric.SetUnit(metricUnit)
-				metric.SetEmptyGauge()
+				metric.SetDescription(metricDescription)
+				initRuntimeMetric(metric, m.Name, cfg.SemconvMetricNames)
 				metricsMap[metricName] = metric
 			}
 
-			// Add data point
-			dp := metric.Gauge().DataPoints().AppendEmpty()
-			dp.SetTimestamp(pcommon.NewTimestampFromTime(eventWallTime(ev.Time(), clockSnap)))
-			// Flight recorder metrics are uint64 values
-			dp.SetDoubleValue(float64(m.Value.Uint64()))
+			// The runtime accumulates cumulative metrics since the start of
+			// the program, which is unknown. Use the start of the first
+			// flight record of the source instead.
+			appendRuntimeMetricDataPoint(metric, m, eventWallTime(ev.Time(), clockSnap), history.cumulativeStart(firstTS))
 
 			continue eventLoop
-		case trace.EventLabel, trace.EventExperimental:
-			// Skip these events for the moment.
-			// TODO: Figure out if and how these can be represented in OTel Profiles
+		case trace.EventLabel:
+			// Labels of goroutines, e.g. of GC mark workers, are attached to
+			// their subsequent samples.
+			if l := ev.Label(); l.Resource.Kind == trace.ResourceGoroutine {
+				labels[l.Resource.Goroutine()] = l.Label
+				if clockSnap != nil {
+					// The label can turn a running goroutine into a GC mark worker.
+					utilization.update(eventWallTime(ev.Time(), clockSnap))
+				}
+			}
 			continue eventLoop
-		case trace.EventRangeBegin:
+		case trace.EventExperimental:
+			e := ev.Experimental()
+			if e.Experiment != experimentAllocFree {
+				// Skip other experiments for the moment.
+				quality.drop(metadata.AttributeFlightrecorderDropReasonUnsupportedKind, ev.Kind())
+				continue eventLoop
+			}
 			if clockSnap == nil {
-				logger.Error("received EventRangeBegin before clock synchonization")
+				logger.Error("received EventExperimental before clock synchonization")
+				quality.drop(metadata.AttributeFlightrecorderDropReasonBeforeClockSync, ev.Kind())
 				continue eventLoop
 			}
-			// Finalize any previously initialized state for this goroutine before
-			// replacing it; otherwise its profile would remain at time=0, duration=0.
-			if existing, ok := activeRanges[ev.Goroutine()]; ok && existing.initialized {
-				beginTS := eventWallTime(ev.Time(), clockSnap)
-				existing.profile.SetTime(pcommon.NewTimestampFromTime(existing.startTS))
-				existing.profile.SetDurationNano(uint64(beginTS.Sub(existing.startTS).Nanoseconds()) + 1)
+			goID := ev.Goroutine()
+			var attrs []int32
+			if startFn, ok := startFunctions[goID]; ok {
+				attrs = append(attrs, lt.AddKeyValueUnit(attrGoroutineStartFunction, startFn, ""))
 			}
-			activeRanges[ev.Goroutine()] = &rangeState{
-				startTS: eventWallTime(ev.Time(), clockSnap),
+			linkIdx := links.index(annotations.activeTask(goID))
+			heap.handleEvent(e, eventWallTime(ev.Time(), clockSnap), linkIdx, attrs)
+			continue eventLoop
+		case trace.EventTaskBegin, trace.EventTaskEnd, trace.EventRegionBegin, trace.EventRegionEnd:
+			if clockSnap == nil {
+				logger.Error(fmt.Sprintf("received %s before clock synchonization", ev.Kind()))
+				quality.drop(metadata.AttributeFlightrecorderDropReasonBeforeClockSync, ev.Kind())
+				continue eventLoop
 			}
-			// Fall through to add a sample at startTS.
-		case trace.EventRangeEnd:
-			goID := ev.Goroutine()
-			state, ok := activeRanges[goID]
-			if !ok {
-				logger.Error("received EventRangeEnd without matching EventRangeBegin")
+			wallclockTS := eventWallTime(ev.Time(), clockSnap)
+			switch ev.Kind() {
+			case trace.EventTaskBegin:
+				task := annotations.beginTask(ev)
+				spans.beginTask(task, ev, wallclockTS)
+				links.beginTask(task)
+			case trace.EventTaskEnd:
+				if task := annotations.endTask(ev); task != nil {
+					spans.endTask(task, wallclockTS)
+				}
+			case trace.EventRegionBegin:
+				region := annotations.beginRegion(ev)
+				spans.beginRegion(region, ev, wallclockTS)
+			case trace.EventRegionEnd:
+				if region := annotations.endRegion(ev); region != nil {
+					spans.endRegion(region, wallclockTS)
+				}
+			}
+			continue eventLoop
+		case trace.EventLog:
+			if clockSnap == nil {
+				logger.Error("received EventLog before clock synchonization")
+				quality.drop(metadata.AttributeFlightrecorderDropReasonBeforeClockSync, ev.Kind())
+				continue eventLoop
+			}
+			links.handleLog(ev)
+			span := annotations.activeSpan(ev.Goroutine(), ev.Log().Task)
+			if ts := eventWallTime(ev.Time(), clockSnap); !history.emitted(ts) {
+				appendLogRecord(currentScopeLogs.LogRecords(), ev, ts, span)
+			}
+			continue eventLoop
+		case trace.EventRangeBegin:
+			if clockSnap == nil {
+				logger.Error("received EventRangeBegin before clock synchonization")
+				quality.drop(metadata.AttributeFlightrecorderDropReasonBeforeClockSync, ev.Kind())
 				continue eventLoop
 			}
-			delete(activeRanges, goID)
-			if !state.initialized {
-				// Do not generate data with empty profiles
+			gc.handleEvent(ev, eventWallTime(ev.Time(), clockSnap))
+			goroutines.handleRange(ev, eventWallTime(ev.Time(), clockSnap))
+			utilization.handleRange(ev, eventWallTime(ev.Time(), clockSnap))
+			heapTrajectory.handleRange(ev, eventWallTime(ev.Time(), clockSnap))
+			anomalies.handleRange(ev, eventWallTime(ev.Time(), clockSnap))
+			ranges.begin(ev.Range())
+			// Fall through to add a sample at the begin of the range.
+		case trace.EventRangeEnd:
+			if clockSnap == nil {
+				logger.Error("received EventRangeEnd before clock synchonization")
+				quality.drop(metadata.AttributeFlightrecorderDropReasonBeforeClockSync, ev.Kind())
 				continue eventLoop
 			}
-			endTS := eventWallTime(ev.Time(), clockSnap)
-			state.profile.SetTime(pcommon.NewTimestampFromTime(state.startTS))
-			// Use +1 so the half-open range [startTS, endTS+1) includes any sample
-			// that lands at exactly endTS (state transitions can share a timestamp
-			// with the range-end event).
-			state.profile.SetDurationNano(uint64(endTS.Sub(state.startTS).Nanoseconds()) + 1)
+			gc.handleEvent(ev, eventWallTime(ev.Time(), clockSnap))
+			goroutines.handleRange(ev, eventWallTime(ev.Time(), clockSnap))
+			utilization.handleRange(ev, eventWallTime(ev.Time(), clockSnap))
+			heapTrajectory.handleRange(ev, eventWallTime(ev.Time(), clockSnap))
+			anomalies.handleRange(ev, eventWallTime(ev.Time(), clockSnap))
+			// Ranges may begin and end on any goroutine. The samples of a
+			// goroutine move to the state of another range with its next
+			// sample.
+			if !ranges.end(ev.Range()) {
+				logger.Error("received EventRangeEnd without matching EventRangeBegin")
+				quality.drop(metadata.AttributeFlightrecorderDropReasonUnmatchedRangeEnd, ev.Kind())
+			}
 			continue eventLoop
 		case trace.EventStateTransition:
 			if clockSnap == nil {
 				logger.Error("received EventStateTransition before clock synchonization")
+				quality.drop(metadata.AttributeFlightrecorderDropReasonBeforeClockSync, ev.Kind())
 				continue eventLoop
 			}
+			st := ev.StateTransition()
+			if st.Resource.Kind == trace.ResourceGoroutine {
+				stGoID := st.Resource.Goroutine()
+				goroutines.handleTransition(st, eventWallTime(ev.Time(), clockSnap))
+				utilization.handleTransition(ev, st, eventWallTime(ev.Time(), clockSnap))
+				census.handleTransition(st, eventWallTime(ev.Time(), clockSnap))
+				goroutineCount.handleTransition(st)
+				anomalies.handleTransition(st, eventWallTime(ev.Time(), clockSnap))
+				if _, ok := startFunctions[stGoID]; !ok {
+					if startFn := rootFunction(st.Stack); startFn != "" {
+						startFunctions[stGoID] = startFn
+					}
+				}
+				if from, to := st.Goroutine(); from == trace.GoNotExist && to == trace.GoRunnable && hasFrames(ev.Stack()) {
+					wallclockTS := eventWallTime(ev.Time(), clockSnap)
+					creations := groups.get("goroutine_creations", "count", nil, wallclockTS)
+					creations.cover(wallclockTS, wallclockTS)
+					startFnAttr := lt.AddKeyValueUnit(attrGoroutineStartFunction, startFunctions[stGoID], "")
+					linkIdx := links.index(annotations.activeTask(ev.Goroutine()))
+					creations.addSample(stackIndex(lt, ev.Stack()), linkIdx, []int32{startFnAttr}, wallclockTS, 1, cfg.AggregateSamples)
+				}
+			}
 			// Just unwind the stack — fall through to add a sample.
 		default:
 			logger.Debug(fmt.Sprintf("Skipping event kind %s", ev.Kind().String()))
+			quality.drop(metadata.AttributeFlightrecorderDropReasonUnsupportedKind, ev.Kind())
 			continue eventLoop
 		}
 
-		eventHasFrames := false
-		for range ev.Stack().Frames() {
-			eventHasFrames = true
-			break
-		}
-		if !eventHasFrames {
+		if !hasFrames(ev.Stack()) {
 			// Ignore events without a stack
 			continue
 		}
 
 		// EventRangeBegin and EventStateTransition fall through to here.
 		goID := ev.Goroutine()
+		if cfg.ExcludeGCWorkers && isGCWorker(startFunctions[goID], labels[goID]) {
+			continue
+		}
+		wallclockTS := eventWallTime(ev.Time(), clockSnap)
+		rangeName := ranges.innermost(goID)
 		state, ok := activeRanges[goID]
+		if ok && state.name != rangeName {
+			// The goroutine entered or left a range since its previous
+			// sample, whose value was finished by this event already.
+			state.end(wallclockTS)
+			ok = false
+		}
 		if !ok {
 			state = &rangeState{
-				startTS: eventWallTime(ev.Time(), clockSnap),
+				name:    rangeName,
+				startTS: wallclockTS,
+			}
+			activeRanges[goID] = state
+			if rangeName == "" {
+				logger.Warn(fmt.Sprintf("Received event for GoID %v without prior EventRangeBegin", goID))
+				quality.withoutRangeBegin[goID] = true
 			}
-			activeRanges[ev.Goroutine()] = state
-			logger.Warn(fmt.Sprintf("Received event for GoID %v without prior EventRangeBegin", goID))
-		}
-
-		if !state.initialized {
-			// Lazily create the ScopeProfile and Profile on the first sample.
-			sp := spSlice.AppendEmpty()
-			sp.SetSchemaUrl(semconv.SchemaURL)
-			state.profile = sp.Profiles().AppendEmpty()
-			initializeProfile(lt, state.profile)
-			state.initialized = true
 		}
 
-		wallclockTS := eventWallTime(ev.Time(), clockSnap)
-		state.lastEventTS = wallclockTS
-		newSample := state.profile.Samples().AppendEmpty()
-
 		// GoID is not part of OTel SemConv - so hardcode it here.
-		goIDAttr := lt.AddKeyValueUnit("GoID", strconv.Itoa(int(goID)), "")
-		newSample.AttributeIndices().Append(goIDAttr)
-
-		if err := populateSample(lt, newSample, ev.Stack(), wallclockTS.UnixNano()); err != nil {
-			return pprofile.Profiles{}, pmetric.Metrics{}, err
+		attrs := []int32{lt.AddKeyValueUnit(attrGoID, strconv.Itoa(int(goID)), "")}
+		if startFn, ok := startFunctions[goID]; ok {
+			attrs = append(attrs, lt.AddKeyValueUnit(attrGoroutineStartFunction, startFn, ""))
 		}
+		if label, ok := labels[goID]; ok {
+			attrs = append(attrs, lt.AddKeyValueUnit(attrGoroutineLabel, label, ""))
+		}
+		if state.name != "" {
+			attrs = append(attrs, lt.AddKeyValueUnit(attrRange, state.name, ""))
+		}
+		attrs = appendExecutionContextAttributes(lt, attrs, ev)
+		attrs = annotations.appendAttributes(lt, attrs, goID, cfg.RegionPath)
+
+		wall := groups.get("wall", "nanoseconds", state, wallclockTS)
+		state.addSample(wall, stackIndex(lt, ev.Stack()), links.index(annotations.activeTask(goID)), attrs, wallclockTS, cfg.AggregateSamples)
 	}
 
+	// Objects that are still alive add samples and possibly new profiles,
+	// so they need to be handled before the dictionary is populated.
+	heap.finish(lastTS)
+
 	if err := populateDictionary(lt, profiles.Dictionary()); err != nil {
-		return pprofile.Profiles{}, pmetric.Metrics{}, err
+		return signals{}, err
 	}
 
 	// Dump remaining events that were missing proper EventRangeBegin and EventRangeEnd.
+	// Without an EventRangeEnd a range lasts until the end of the trace.
 	for _, state := range activeRanges {
-		if !state.initialized {
-			continue
+		state.end(lastTS)
+	}
+	groups.finalize()
+	spans.finish(lastTS)
+	anomalies.finish(lastTS)
+	for _, metric := range metricsMap {
+		downsampleRuntimeMetric(metric, cfg.MetricsResolution, cfg.MetricsAggregation, cfg.MetricsSummary)
+	}
+	if cfg.SemconvMetricNames && !firstTS.IsZero() {
+		goroutineCount.appendTo(currentScopeMetric.Metrics(), history.cumulativeStart(firstTS), lastTS)
+	}
+	// The quality of a trace is reported even if it has no clock snapshot
+	// and thus no wall time.
+	start, end := firstTS, lastTS
+	if firstTS.IsZero() {
+		start = time.Now()
+		end = start
+	} else if history.emitted(start) {
+		// The derived delta sums only count what happened after the end of
+		// the previous flight record of the source.
+		start = history.end
+	}
+	mb := metadata.NewMetricsBuilder(cfg.MetricsBuilderConfig, set,
+		metadata.WithStartTime(pcommon.NewTimestampFromTime(start)))
+	quality.recordMetrics(mb, end)
+	if !firstTS.IsZero() {
+		gc.recordMetrics(mb, lastTS)
+		goroutines.recordMetrics(mb, startFunctions, lastTS)
+		utilization.recordMetrics(mb, cfg.MMUWindows, firstTS, lastTS)
+		census.recordMetrics(mb, lastTS)
+		heapTrajectory.recordMetrics(mb)
+		threads.recordMetrics(mb, firstTS, lastTS)
+	}
+
+	// The metrics of the builder belong to the scope of the runtime metrics.
+	built := mb.Emit().ResourceMetrics()
+	for i := 0; i < built.Len(); i++ {
+		sms := built.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			sms.At(j).Metrics().MoveAndAppendTo(currentScopeMetric.Metrics())
 		}
-		state.profile.SetTime(pcommon.NewTimestampFromTime(state.startTS))
-		// Use +1 so the half-open range [startTS, lastEventTS+1) includes the
-		// last sample, which is at lastEventTS.
-		state.profile.SetDurationNano(uint64(state.lastEventTS.Sub(state.startTS).Nanoseconds()) + 1)
+	}
+	if !firstTS.IsZero() && cfg.Histograms.GoGcStwDuration.Enabled {
+		gc.appendSTWPauses(currentScopeMetric.Metrics(), start, lastTS)
+	}
+	groups.appendExemplars(slices.Collect(maps.Values(metricsMap)))
+
+	return signals{
+		profiles: profiles,
+		metrics:  metrics,
+		traces:   traces,
+		logs:     logs,
+		start:    firstTS,
+		end:      lastTS,
+	}, nil
+}
+
+// appendExecutionContextAttributes appends the P and thread the event was
+// executed on to attrs. For state transitions of the goroutine executing the
+// event, the states and the reason of the transition are appended as well.
+func appendExecutionContextAttributes(lt lookupTable, attrs []int32, ev trace.Event) []int32 {
+	if procID := ev.Proc(); procID != trace.NoProc {
+		attrs = append(attrs, lt.AddKeyIntValueUnit(attrProcID, int64(procID), ""))
+	}
+	if threadID := ev.Thread(); threadID != trace.NoThread {
+		attrs = append(attrs, lt.AddKeyIntValueUnit(string(semconv.ThreadIDKey), int64(threadID), ""))
 	}
 
-	return profiles, metrics, nil
+	if ev.Kind() != trace.EventStateTransition {
+		return attrs
+	}
+	st := ev.StateTransition()
+	// The stack of a transition for another goroutine, e.g. when waking it up,
+	// belongs to the goroutine executing the event. Its state is unchanged.
+	if st.Resource.Kind != trace.ResourceGoroutine || st.Resource.Goroutine() != ev.Goroutine() {
+		return attrs
+	}
+	from, to := st.Goroutine()
+	attrs = append(attrs,
+		lt.AddKeyValueUnit(attrGoroutineStateFrom, from.String(), ""),
+		lt.AddKeyValueUnit(attrGoroutineStateTo, to.String(), ""))
+	if st.Reason != "" {
+		attrs = append(attrs, lt.AddKeyValueUnit(attrGoroutineStateReason, st.Reason, ""))
+	}
+	return attrs
 }
 
-// initializeProfile sets default values for Profile.
-func initializeProfile(lt lookupTable, p pprofile.Profile) {
-	// Report the flightrecord as wallclock profile.
-	p.SampleType().SetTypeStrindex(lt.AddString("wall"))
-	p.SampleType().SetUnitStrindex(lt.AddString("nanoseconds"))
+// isGCWorker reports whether a goroutine with the given start function and
+// label is a GC mark worker.
+func isGCWorker(startFunction, label string) bool {
+	// The runtime labels GC mark workers with their mode, e.g. "GC (idle)".
+	return startFunction == "runtime.gcBgMarkWorker" || strings.HasPrefix(label, "GC (")
 }
 
-// populateSample converts a single trace.Stack into a pprofile.Sample.
-func populateSample(lt lookupTable, s pprofile.Sample, stack trace.Stack, ts int64) error {
-	s.TimestampsUnixNano().Append(uint64(ts))
+// hasFrames reports whether stack holds at least one frame.
+func hasFrames(stack trace.Stack) bool {
+	for range stack.Frames() {
+		return true
+	}
+	return false
+}
 
+// rootFunction returns the function of the outermost frame of stack.
+// For the stack of a goroutine state transition this is the function the
+// goroutine was started with.
+func rootFunction(stack trace.Stack) string {
+	var name string
+	for frame := range stack.Frames() {
+		name = frame.Func
+	}
+	return name
+}
+
+// stackIndex converts a single trace.Stack into an index of the stack table.
+func stackIndex(lt lookupTable, stack trace.Stack) int32 {
 	var li []int32
 	for frame := range stack.Frames() {
 		loc := lt.AddLocation(frame)
 		li = append(li, loc)
 	}
 
-	si := lt.AddStack(li)
-	s.SetStackIndex(si)
-
-	return nil
+	return lt.AddStack(li)
 }
 
 // eventWallTime calculates the absolute time.Time for a given event based
@@ -283,10 +573,14 @@ func populateDictionary(lt lookupTable, dic pprofile.ProfilesDictionary) error {
 		dic.FunctionTable().At(int(idx)).SetStartLine(a.startLine)
 	}
 
-	// links - currently not used
-	// As LinkTable is not initizalied in createLookupTable
-	// create the empty element here.
-	dic.LinkTable().AppendEmpty()
+	// links
+	for range lt.links {
+		dic.LinkTable().AppendEmpty()
+	}
+	for l, idx := range lt.links {
+		dic.LinkTable().At(int(idx)).SetTraceID(l.traceID)
+		dic.LinkTable().At(int(idx)).SetSpanID(l.spanID)
+	}
 
 	// strings
 	for range lt.strings {
@@ -303,6 +597,10 @@ func populateDictionary(lt lookupTable, dic pprofile.ProfilesDictionary) error {
 	for a, idx := range lt.attributes {
 		dic.AttributeTable().At(int(idx)).SetKeyStrindex(a.keyIdx)
 		dic.AttributeTable().At(int(idx)).SetUnitStrindex(a.unitIdx)
+		if a.isInt {
+			dic.AttributeTable().At(int(idx)).Value().SetInt(a.intValue)
+			continue
+		}
 		dic.AttributeTable().At(int(idx)).Value().SetStr(a.value)
 	}
 
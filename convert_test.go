@@ -1,18 +1,30 @@
 package flightrecorderreceiver
 
 import (
+	"bytes"
 	"io"
 	"os"
+	"runtime"
 	"runtime/trace"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/open-telemetry/sig-profiling/profcheck"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
 	"go.opentelemetry.io/collector/pdata/pprofile/pprofileotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
 	v1profiles "go.opentelemetry.io/proto/otlp/profiles/v1development"
-	"go.uber.org/zap"
+	exptrace "golang.org/x/exp/trace"
 	"google.golang.org/protobuf/proto"
+
+	"github.com/florianl/flightrecorderreceiver/internal/metadata"
 )
 
 func primeFactors(t *testing.T, n int) []int {
@@ -38,6 +50,12 @@ func fibonacci(t *testing.T, n uint32) uint32 {
 	return fibonacci(t, n-1) + fibonacci(t, n-2)
 }
 
+// IDs of the distributed trace a task of generateFlightrecord is linked to.
+const (
+	testTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanID  = "00f067aa0ba902b7"
+)
+
 func generateFlightrecord(t *testing.T) (io.Reader, func() error) {
 	t.Helper()
 	f, err := os.CreateTemp(t.TempDir(), "flightrecord-*.out")
@@ -52,21 +70,52 @@ func generateFlightrecord(t *testing.T) (io.Reader, func() error) {
 		t.Fatal(err)
 	}
 
+	ctx, task := trace.NewTask(t.Context(), "generateFlightrecord")
+
 	var wg sync.WaitGroup
 
 	wg.Go(
 		func() {
-			trace.WithRegion(t.Context(), "primeFactors", func() {
+			trace.WithRegion(ctx, "primeFactors", func() {
 				list := primeFactors(t, 73*73)
 				_ = list
+				// Yield to guarantee a sample within the region.
+				runtime.Gosched()
+			})
+		})
+
+	wg.Go(
+		func() {
+			trace.WithRegion(ctx, "fibonacci", func() {
+				trace.Log(ctx, "input", "23")
+				fibonacci(t, 23)
 			})
 		})
 
 	wg.Go(
 		func() {
-			trace.WithRegion(t.Context(), "fibonacci", func() { fibonacci(t, 23) })
+			// Carry the IDs of a distributed trace in the task name, as
+			// instrumented code does to link it to the runtime trace.
+			_, linked := trace.NewTask(ctx, "linked trace_id="+testTraceID+" span_id="+testSpanID)
+			defer linked.End()
+			_ = primeFactors(t, 97*97)
+			runtime.Gosched()
 		})
 	wg.Wait()
+	task.End()
+
+	// Run a GC cycle to have GC mark workers in the trace.
+	runtime.GC()
+	// Allocate after the cycle to have spans swept on demand, within ranges
+	// that are scoped to a P.
+	var garbage [][]byte
+	for i := range 1 << 12 {
+		garbage = append(garbage, make([]byte, 1<<10))
+		if i%64 == 0 {
+			runtime.Gosched()
+		}
+	}
+	runtime.KeepAlive(garbage)
 
 	trace.Stop()
 
@@ -77,16 +126,34 @@ func generateFlightrecord(t *testing.T) (io.Reader, func() error) {
 	return f, cleanup
 }
 
+// profilesOfType returns all profiles in p with the given sample type.
+func profilesOfType(p pprofile.Profiles, sampleType string) []pprofile.Profile {
+	var result []pprofile.Profile
+	for _, rp := range p.ResourceProfiles().All() {
+		for _, sp := range rp.ScopeProfiles().All() {
+			for _, prof := range sp.Profiles().All() {
+				if p.Dictionary().StringTable().At(int(prof.SampleType().TypeStrindex())) == sampleType {
+					result = append(result, prof)
+				}
+			}
+		}
+	}
+	return result
+}
+
 func TestConvert(t *testing.T) {
 	f, cleanup := generateFlightrecord(t)
 	defer cleanup()
 
-	logger := zap.NewNop()
+	cfg := createDefaultConfig().(*Config)
+	// The flight record is shorter than the default census resolution.
+	cfg.CensusResolution = time.Millisecond
 
-	p, m, err := convert(t.Context(), logger, f)
+	converted, err := convert(t.Context(), receivertest.NewNopSettings(metadata.Type), cfg, f, sourceHistory{})
 	if err != nil {
 		t.Fatal(err)
 	}
+	p, m := converted.profiles, converted.metrics
 
 	// Verify both profiles and metrics were extracted
 	if p.ResourceProfiles().Len() == 0 {
@@ -132,6 +199,465 @@ func TestConvert(t *testing.T) {
 			t.Fatalf("conformance check failed: %v", err)
 		}
 	})
+	t.Run("GoroutineCreations", func(t *testing.T) {
+		creations := profilesOfType(p, "goroutine_creations")
+		if len(creations) != 1 {
+			t.Fatalf("expected 1 goroutine_creations profile, got %d", len(creations))
+		}
+		dic := p.Dictionary()
+		found := false
+		for _, sample := range creations[0].Samples().All() {
+			if sample.Values().Len() != 1 || sample.Values().At(0) != 1 {
+				t.Fatalf("expected a count of 1, got %v", sample.Values().AsRaw())
+			}
+			for _, idx := range sample.AttributeIndices().All() {
+				attr := dic.AttributeTable().At(int(idx))
+				if dic.StringTable().At(int(attr.KeyStrindex())) != attrGoroutineStartFunction {
+					continue
+				}
+				// generateFlightrecord starts its goroutines with sync.WaitGroup.Go.
+				if attr.Value().Str() == "sync.(*WaitGroup).Go.func1" {
+					found = true
+				}
+			}
+		}
+		if !found {
+			t.Fatal("expected goroutines started by sync.WaitGroup.Go")
+		}
+	})
+	t.Run("ExecutionContextAttributes", func(t *testing.T) {
+		dic := p.Dictionary()
+		keys := make(map[string]pcommon.ValueType)
+		for _, prof := range profilesOfType(p, "wall") {
+			for _, sample := range prof.Samples().All() {
+				for _, idx := range sample.AttributeIndices().All() {
+					attr := dic.AttributeTable().At(int(idx))
+					keys[dic.StringTable().At(int(attr.KeyStrindex()))] = attr.Value().Type()
+				}
+			}
+		}
+		for key, typ := range map[string]pcommon.ValueType{
+			attrGoID:                 pcommon.ValueTypeStr,
+			attrProcID:               pcommon.ValueTypeInt,
+			"thread.id":              pcommon.ValueTypeInt,
+			attrGoroutineStateFrom:   pcommon.ValueTypeStr,
+			attrGoroutineStateTo:     pcommon.ValueTypeStr,
+			attrGoroutineStateReason: pcommon.ValueTypeStr,
+		} {
+			got, ok := keys[key]
+			if !ok {
+				t.Errorf("expected attribute %q on wall samples", key)
+			} else if got != typ {
+				t.Errorf("expected attribute %q of type %s, got %s", key, typ, got)
+			}
+		}
+	})
+	t.Run("WallValues", func(t *testing.T) {
+		for _, prof := range profilesOfType(p, "wall") {
+			var total int64
+			for _, sample := range prof.Samples().All() {
+				if sample.Values().Len() != sample.TimestampsUnixNano().Len() {
+					t.Fatalf("expected one value per timestamp, got %d values and %d timestamps",
+						sample.Values().Len(), sample.TimestampsUnixNano().Len())
+				}
+				for _, v := range sample.Values().All() {
+					if v < 0 {
+						t.Fatalf("expected non-negative wall time, got %d", v)
+					}
+					total += v
+				}
+			}
+			if uint64(total) > prof.DurationNano() {
+				t.Fatalf("sample values add up to %d ns, which exceeds the profile duration of %d ns",
+					total, prof.DurationNano())
+			}
+		}
+	})
+	t.Run("UserAnnotations", func(t *testing.T) {
+		var taskID int64
+		for _, rs := range converted.traces.ResourceSpans().All() {
+			for _, ss := range rs.ScopeSpans().All() {
+				for _, span := range ss.Spans().All() {
+					if span.Name() == "generateFlightrecord" {
+						id, _ := span.Attributes().Get(attrTaskID)
+						taskID = id.Int()
+					}
+				}
+			}
+		}
+		dic := p.Dictionary()
+		for _, prof := range profilesOfType(p, "wall") {
+			for _, sample := range prof.Samples().All() {
+				attrs := make(map[string]pcommon.Value)
+				for _, idx := range sample.AttributeIndices().All() {
+					attr := dic.AttributeTable().At(int(idx))
+					attrs[dic.StringTable().At(int(attr.KeyStrindex()))] = attr.Value()
+				}
+				if attrs[attrRegion].Str() != "primeFactors" || attrs[attrTaskType].Str() != "generateFlightrecord" {
+					continue
+				}
+				// The task ID has the same type as on spans and log records.
+				if id := attrs[attrTaskID]; id.Type() != pcommon.ValueTypeInt || id.Int() != taskID {
+					t.Fatalf("expected task ID %d, got %v", taskID, id.AsRaw())
+				}
+				return
+			}
+		}
+		t.Fatal("expected a sample within region primeFactors of task generateFlightrecord")
+	})
+	t.Run("Traces", func(t *testing.T) {
+		spans := make(map[string]ptrace.Span)
+		for _, rs := range converted.traces.ResourceSpans().All() {
+			for _, ss := range rs.ScopeSpans().All() {
+				for _, span := range ss.Spans().All() {
+					spans[span.Name()] = span
+				}
+			}
+		}
+		task, ok := spans["generateFlightrecord"]
+		if !ok {
+			t.Fatal("expected a span for the task")
+		}
+		taskID, ok := task.Attributes().Get(attrTaskID)
+		if !ok {
+			t.Fatalf("expected attribute %q on task span", attrTaskID)
+		}
+		for _, name := range []string{"primeFactors", "fibonacci"} {
+			region, ok := spans[name]
+			if !ok {
+				t.Fatalf("expected a span for region %q", name)
+			}
+			if region.TraceID() != task.TraceID() || region.ParentSpanID() != task.SpanID() {
+				t.Fatalf("expected region %q to be a child of the task span", name)
+			}
+			if region.StartTimestamp() < task.StartTimestamp() || region.EndTimestamp() > task.EndTimestamp() {
+				t.Fatalf("expected region %q to be within the task span", name)
+			}
+			if _, ok := region.Attributes().Get(attrGoID); !ok {
+				t.Fatalf("expected attribute %q on region %q", attrGoID, name)
+			}
+			if regionTaskID, _ := region.Attributes().Get(attrTaskID); regionTaskID.Int() != taskID.Int() {
+				t.Fatalf("expected region %q to belong to task %d", name, taskID.Int())
+			}
+		}
+	})
+	t.Run("Logs", func(t *testing.T) {
+		var region ptrace.Span
+		for _, rs := range converted.traces.ResourceSpans().All() {
+			for _, ss := range rs.ScopeSpans().All() {
+				for _, span := range ss.Spans().All() {
+					if span.Name() == "fibonacci" {
+						region = span
+					}
+				}
+			}
+		}
+		if converted.logs.LogRecordCount() != 1 {
+			t.Fatalf("expected 1 log record, got %d", converted.logs.LogRecordCount())
+		}
+		record := converted.logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+		if record.Body().Str() != "23" {
+			t.Fatalf("expected body %q, got %q", "23", record.Body().Str())
+		}
+		if category, _ := record.Attributes().Get(attrLogCategory); category.Str() != "input" {
+			t.Fatalf("expected category %q, got %q", "input", category.Str())
+		}
+		if _, ok := record.Attributes().Get(attrTaskID); !ok {
+			t.Fatalf("expected attribute %q on log record", attrTaskID)
+		}
+		if record.TraceID() != region.TraceID() || record.SpanID() != region.SpanID() {
+			t.Fatal("expected log record to be correlated with the enclosing region")
+		}
+	})
+	t.Run("GCMetrics", func(t *testing.T) {
+		gcMetrics := make(map[string]pmetric.Metric)
+		for _, metric := range m.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+			gcMetrics[metric.Name()] = metric
+		}
+		// generateFlightrecord runs a GC cycle.
+		cycles, ok := gcMetrics["go.gc.cycles"]
+		if !ok {
+			t.Fatal("expected metric go.gc.cycles")
+		}
+		if got := cycles.Sum().DataPoints().At(0).IntValue(); got < 1 {
+			t.Fatalf("expected at least 1 GC cycle, got %d", got)
+		}
+		if _, ok := gcMetrics["go.gc.mark_assist.duration"]; !ok {
+			t.Fatal("expected metric go.gc.mark_assist.duration")
+		}
+		pauses, ok := gcMetrics["go.gc.stw.duration"]
+		if !ok {
+			t.Fatal("expected metric go.gc.stw.duration")
+		}
+		reasons := make(map[string]bool)
+		for _, dp := range pauses.Histogram().DataPoints().All() {
+			if dp.Count() == 0 {
+				t.Fatal("expected pauses in every data point")
+			}
+			reason, _ := dp.Attributes().Get(attrSTWReason)
+			reasons[reason.Str()] = true
+		}
+		if !reasons["GC mark termination"] {
+			t.Fatalf("expected a stop-the-world pause for GC mark termination, got %v", reasons)
+		}
+
+		if _, err := f.(io.Seeker).Seek(0, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		intervals := rangeIntervals(t, f)
+		dic := p.Dictionary()
+		ranges := make(map[string]bool)
+		for _, prof := range profilesOfType(p, "wall") {
+			for _, sample := range prof.Samples().All() {
+				var goID exptrace.GoID
+				var rangeName string
+				for _, idx := range sample.AttributeIndices().All() {
+					attr := dic.AttributeTable().At(int(idx))
+					switch dic.StringTable().At(int(attr.KeyStrindex())) {
+					case attrGoID:
+						id, _ := strconv.ParseInt(attr.Value().Str(), 10, 64)
+						goID = exptrace.GoID(id)
+					case attrRange:
+						rangeName = attr.Value().Str()
+					}
+				}
+				if rangeName == "" {
+					continue
+				}
+				ranges[rangeName] = true
+				// The range must have been active at every observation and
+				// apply to the goroutine of the sample.
+				for _, ts := range sample.TimestampsUnixNano().All() {
+					if !slices.ContainsFunc(intervals, func(r testRange) bool {
+						return r.name == rangeName && r.appliesTo(goID) && r.begin <= int64(ts) && int64(ts) <= r.end
+					}) {
+						t.Fatalf("sample of goroutine %d at %d is not within range %q", goID, ts, rangeName)
+					}
+				}
+			}
+		}
+		if !ranges[rangeGCMarkPhase] {
+			t.Fatalf("expected samples within range %q, got %v", rangeGCMarkPhase, ranges)
+		}
+	})
+	t.Run("GoroutineMetrics", func(t *testing.T) {
+		byName := make(map[string]pmetric.Metric)
+		for _, metric := range m.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+			byName[metric.Name()] = metric
+		}
+		for _, name := range []string{
+			"go.goroutine.execution.duration",
+			"go.goroutine.sched_wait.duration",
+			"go.goroutine.sync_block.duration",
+			"go.goroutine.syscall.duration",
+			"go.goroutine.network_wait.duration",
+			"go.goroutine.gc_assist.duration",
+		} {
+			if _, ok := byName[name]; !ok {
+				t.Fatalf("expected metric %s", name)
+			}
+		}
+		// The goroutines started by generateFlightrecord do some work.
+		var exec float64
+		for _, dp := range byName["go.goroutine.execution.duration"].Sum().DataPoints().All() {
+			if startFn, _ := dp.Attributes().Get(attrGoroutineStartFunction); startFn.Str() == "sync.(*WaitGroup).Go.func1" {
+				exec = dp.DoubleValue()
+			}
+		}
+		if exec <= 0 {
+			t.Fatal("expected execution time of goroutines started by sync.WaitGroup.Go")
+		}
+	})
+	t.Run("UtilizationMetrics", func(t *testing.T) {
+		byName := make(map[string]pmetric.Metric)
+		for _, metric := range m.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+			byName[metric.Name()] = metric
+		}
+		utilization, ok := byName["go.proc.utilization"]
+		if !ok || utilization.Gauge().DataPoints().Len() == 0 {
+			t.Fatal("expected utilization of Ps")
+		}
+		for _, dp := range utilization.Gauge().DataPoints().All() {
+			if v := dp.DoubleValue(); v < 0 || v > 1 {
+				t.Fatalf("expected utilization within [0, 1], got %f", v)
+			}
+		}
+		mmu, ok := byName["go.gc.mmu"]
+		if !ok {
+			t.Fatal("expected metric go.gc.mmu")
+		}
+		windows := make(map[string]bool)
+		for _, dp := range mmu.Gauge().DataPoints().All() {
+			if v := dp.DoubleValue(); v < 0 || v > 1 {
+				t.Fatalf("expected MMU within [0, 1], got %f", v)
+			}
+			window, _ := dp.Attributes().Get("go.gc.mmu.window")
+			windows[window.Str()] = true
+		}
+		// The flight record spans more than a millisecond.
+		if !windows[time.Millisecond.String()] {
+			t.Fatalf("expected MMU for a window of 1ms, got %v", windows)
+		}
+	})
+	t.Run("GoroutineCensus", func(t *testing.T) {
+		var census pmetric.Metric
+		for _, metric := range m.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+			if metric.Name() == "go.goroutine.state.count" {
+				census = metric
+			}
+		}
+		if census == (pmetric.Metric{}) {
+			t.Fatal("expected metric go.goroutine.state.count")
+		}
+		maxByState := make(map[string]int64)
+		for _, dp := range census.Gauge().DataPoints().All() {
+			if dp.IntValue() < 0 {
+				t.Fatalf("expected non-negative count, got %d", dp.IntValue())
+			}
+			if dp.Timestamp().AsTime().UnixNano()%cfg.CensusResolution.Nanoseconds() != 0 {
+				t.Fatalf("expected samples aligned to %s", cfg.CensusResolution)
+			}
+			state, _ := dp.Attributes().Get("go.goroutine.state")
+			maxByState[state.Str()] = max(maxByState[state.Str()], dp.IntValue())
+		}
+		for _, state := range []string{"running", "runnable", "waiting", "syscall"} {
+			if _, ok := maxByState[state]; !ok {
+				t.Fatalf("expected samples for state %q", state)
+			}
+		}
+		if maxByState["running"] == 0 {
+			t.Fatal("expected samples with running goroutines")
+		}
+	})
+	t.Run("HeapTrajectory", func(t *testing.T) {
+		byName := make(map[string]pmetric.Metric)
+		for _, metric := range m.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+			byName[metric.Name()] = metric
+		}
+		size := byName[metadata.MetricsInfo.GoHeapSize.Name].Gauge().DataPoints()
+		goal := byName[metadata.MetricsInfo.GoHeapGoal.Name].Gauge().DataPoints()
+		headroom := byName[metadata.MetricsInfo.GoHeapHeadroom.Name].Gauge().DataPoints()
+		if size.Len() == 0 || size.Len() != goal.Len() || size.Len() != headroom.Len() {
+			t.Fatalf("expected aligned heap series, got %d, %d and %d data points", size.Len(), goal.Len(), headroom.Len())
+		}
+		var maxCycle int64
+		for i := 0; i < size.Len(); i++ {
+			if size.At(i).Timestamp() != goal.At(i).Timestamp() || size.At(i).Timestamp() != headroom.At(i).Timestamp() {
+				t.Fatalf("expected data points at the same time at %d", i)
+			}
+			if got, want := headroom.At(i).IntValue(), goal.At(i).IntValue()-size.At(i).IntValue(); got != want {
+				t.Fatalf("expected headroom %d, got %d", want, got)
+			}
+			cycle, _ := size.At(i).Attributes().Get("go.gc.cycle")
+			maxCycle = max(maxCycle, cycle.Int())
+		}
+		// generateFlightrecord runs a GC cycle.
+		if maxCycle == 0 {
+			t.Fatal("expected data points within a GC cycle")
+		}
+	})
+	t.Run("ThreadMetrics", func(t *testing.T) {
+		byName := make(map[string]pmetric.Metric)
+		for _, metric := range m.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+			byName[metric.Name()] = metric
+		}
+		for _, name := range []string{
+			metadata.MetricsInfo.GoThreadCount.Name,
+			metadata.MetricsInfo.GoThreadSyscallPeak.Name,
+			metadata.MetricsInfo.GoProcHandoffRate.Name,
+		} {
+			if _, ok := byName[name]; !ok {
+				t.Fatalf("expected metric %s", name)
+			}
+		}
+		if n := byName[metadata.MetricsInfo.GoThreadCount.Name].Gauge().DataPoints().At(0).IntValue(); n == 0 {
+			t.Fatal("expected threads that emitted events")
+		}
+		if rate := byName[metadata.MetricsInfo.GoProcHandoffRate.Name].Gauge().DataPoints().At(0).DoubleValue(); rate < 0 {
+			t.Fatalf("expected a non-negative handoff rate, got %f", rate)
+		}
+	})
+	t.Run("QualityMetrics", func(t *testing.T) {
+		byName := make(map[string]pmetric.Metric)
+		for _, metric := range m.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+			byName[metric.Name()] = metric
+		}
+		events, ok := byName[metadata.MetricsInfo.FlightrecorderTraceEvents.Name]
+		if !ok || events.Sum().DataPoints().At(0).IntValue() == 0 {
+			t.Fatal("expected the number of events in the trace")
+		}
+		if _, ok := byName[metadata.MetricsInfo.FlightrecorderTraceGoroutinesWithoutRangeBegin.Name]; !ok {
+			t.Fatalf("expected metric %s", metadata.MetricsInfo.FlightrecorderTraceGoroutinesWithoutRangeBegin.Name)
+		}
+		var dropped int64
+		if metric, ok := byName[metadata.MetricsInfo.FlightrecorderTraceEventsDropped.Name]; ok {
+			for _, dp := range metric.Sum().DataPoints().All() {
+				reason, _ := dp.Attributes().Get("flightrecorder.drop.reason")
+				if _, ok := metadata.MapAttributeFlightrecorderDropReason[reason.Str()]; !ok {
+					t.Fatalf("unexpected drop reason %q", reason.Str())
+				}
+				dropped += dp.IntValue()
+			}
+		}
+		if total := events.Sum().DataPoints().At(0).IntValue(); dropped > total {
+			t.Fatalf("expected at most %d dropped events, got %d", total, dropped)
+		}
+	})
+	t.Run("Exemplars", func(t *testing.T) {
+		profileIDs := make(map[string]bool)
+		for _, rp := range p.ResourceProfiles().All() {
+			for _, sp := range rp.ScopeProfiles().All() {
+				for _, profile := range sp.Profiles().All() {
+					if profile.ProfileID().IsEmpty() {
+						t.Fatal("expected profiles to have a profile ID")
+					}
+					profileIDs[profile.ProfileID().String()] = true
+				}
+			}
+		}
+		var goal pmetric.Metric
+		for _, metric := range m.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+			if metric.Name() == "/gc/heap/goal" {
+				goal = metric
+			}
+		}
+		var exemplars int
+		for _, dp := range goal.Gauge().DataPoints().All() {
+			for _, exemplar := range dp.Exemplars().All() {
+				exemplars++
+				if exemplar.Timestamp() != dp.Timestamp() {
+					t.Fatalf("expected exemplar at %s, got %s", dp.Timestamp(), exemplar.Timestamp())
+				}
+				id, ok := exemplar.FilteredAttributes().Get(attrProfileID)
+				if !ok || !profileIDs[id.Str()] {
+					t.Fatalf("expected exemplar to refer to a profile, got %v", exemplar.FilteredAttributes().AsRaw())
+				}
+			}
+		}
+		if exemplars == 0 {
+			t.Fatal("expected exemplars on /gc/heap/goal")
+		}
+		// Only the gauges of runtime/metrics refer to profiles.
+		for _, metric := range m.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+			if strings.HasPrefix(metric.Name(), "/") && metric.Type() == pmetric.MetricTypeGauge {
+				continue
+			}
+			var dps pmetric.NumberDataPointSlice
+			switch metric.Type() {
+			case pmetric.MetricTypeGauge:
+				dps = metric.Gauge().DataPoints()
+			case pmetric.MetricTypeSum:
+				dps = metric.Sum().DataPoints()
+			default:
+				continue
+			}
+			for _, dp := range dps.All() {
+				if dp.Exemplars().Len() > 0 {
+					t.Fatalf("expected no exemplars on %s", metric.Name())
+				}
+			}
+		}
+	})
 	t.Run("Visual inspection", func(t *testing.T) {
 		// Log the converted profiles for visual inspection
 		for _, rp := range p.ResourceProfiles().All() {
@@ -174,10 +700,821 @@ func TestConvert(t *testing.T) {
 			for _, sp := range rp.ScopeMetrics().All() {
 				for i := 0; i < sp.Metrics().Len(); i++ {
 					m := sp.Metrics().At(i)
-					t.Logf("  Metric: %s (unit: %s, data points: %d)",
-						m.Name(), m.Unit(), m.Gauge().DataPoints().Len())
+					t.Logf("  Metric: %s (unit: %s, type: %s)",
+						m.Name(), m.Unit(), m.Type())
+				}
+			}
+		}
+	})
+}
+
+// goroutineEventTimes returns the wall times of the events of each goroutine
+// in the trace, including stackless events and state transitions executed by
+// other goroutines, along with the wall time of the last event.
+func goroutineEventTimes(t *testing.T, f io.Reader) (map[exptrace.GoID][]int64, int64) {
+	t.Helper()
+	r, err := exptrace.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	times := make(map[exptrace.GoID][]int64)
+	var clockSnap *exptrace.ClockSnapshot
+	var last int64
+	for {
+		ev, err := r.ReadEvent()
+		if err == io.EOF {
+			return times, last
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if clockSnap == nil {
+			if ev.Kind() == exptrace.EventSync {
+				clockSnap = ev.Sync().ClockSnapshot
+			}
+			continue
+		}
+		last = eventWallTime(ev.Time(), clockSnap).UnixNano()
+		times[ev.Goroutine()] = append(times[ev.Goroutine()], last)
+		if ev.Kind() == exptrace.EventStateTransition {
+			if st := ev.StateTransition(); st.Resource.Kind == exptrace.ResourceGoroutine {
+				goID := st.Resource.Goroutine()
+				times[goID] = append(times[goID], last)
+			}
+		}
+		if ev.Kind() == exptrace.EventSync && ev.Sync().ClockSnapshot != nil {
+			clockSnap = ev.Sync().ClockSnapshot
+		}
+	}
+}
+
+// testRange is a range of the trace with the wall times of its begin and end.
+type testRange struct {
+	name       string
+	scope      exptrace.ResourceID
+	begin, end int64
+}
+
+// appliesTo reports whether the samples of the goroutine belong to the range.
+func (r testRange) appliesTo(goID exptrace.GoID) bool {
+	return r.scope.Kind == exptrace.ResourceNone ||
+		r.scope.Kind == exptrace.ResourceGoroutine && r.scope.Goroutine() == goID
+}
+
+// rangeIntervals returns the ranges that began after the clock synchronization
+// of the trace. Ranges that did not end last until the last event.
+func rangeIntervals(t *testing.T, f io.Reader) []testRange {
+	t.Helper()
+	r, err := exptrace.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ranges []testRange
+	open := make(map[gcRangeKey]int)
+	var clockSnap *exptrace.ClockSnapshot
+	var last int64
+	for {
+		ev, err := r.ReadEvent()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ev.Kind() == exptrace.EventSync && ev.Sync().ClockSnapshot != nil {
+			clockSnap = ev.Sync().ClockSnapshot
+		}
+		if clockSnap == nil {
+			continue
+		}
+		last = eventWallTime(ev.Time(), clockSnap).UnixNano()
+		switch ev.Kind() {
+		case exptrace.EventRangeBegin:
+			rng := ev.Range()
+			open[gcRangeKey{name: rng.Name, scope: rng.Scope}] = len(ranges)
+			ranges = append(ranges, testRange{name: rng.Name, scope: rng.Scope, begin: last, end: -1})
+		case exptrace.EventRangeEnd:
+			rng := ev.Range()
+			key := gcRangeKey{name: rng.Name, scope: rng.Scope}
+			if i, ok := open[key]; ok {
+				ranges[i].end = last
+				delete(open, key)
+			}
+		}
+	}
+	for i := range ranges {
+		if ranges[i].end < 0 {
+			ranges[i].end = last
+		}
+	}
+	return ranges
+}
+
+func TestConvertPerEventSamples(t *testing.T) {
+	f, cleanup := generateFlightrecord(t)
+	defer cleanup()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.AggregateSamples = false
+
+	converted, err := convert(t.Context(), receivertest.NewNopSettings(metadata.Type), cfg, bytes.NewReader(data), sourceHistory{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := converted.profiles
+	times, last := goroutineEventTimes(t, bytes.NewReader(data))
+
+	dic := p.Dictionary()
+	for _, prof := range profilesOfType(p, "wall") {
+		for _, sample := range prof.Samples().All() {
+			if sample.TimestampsUnixNano().Len() != 1 {
+				t.Fatalf("expected a sample per event, got %d timestamps",
+					sample.TimestampsUnixNano().Len())
+			}
+			var goID exptrace.GoID
+			for _, idx := range sample.AttributeIndices().All() {
+				attr := dic.AttributeTable().At(int(idx))
+				if dic.StringTable().At(int(attr.KeyStrindex())) == attrGoID {
+					id, _ := strconv.ParseInt(attr.Value().Str(), 10, 64)
+					goID = exptrace.GoID(id)
+				}
+			}
+			// The value of a sample must not exceed the time until the next
+			// event of its goroutine, e.g. a stackless GoUnblock.
+			ts := int64(sample.TimestampsUnixNano().At(0))
+			next := last
+			if i, _ := slices.BinarySearch(times[goID], ts+1); i < len(times[goID]) {
+				next = times[goID][i]
+			}
+			if v := sample.Values().At(0); v > next-ts {
+				t.Fatalf("expected a value of at most %d ns for goroutine %d, got %d ns", next-ts, goID, v)
+			}
+		}
+	}
+}
+
+func TestConvertAggregateSamples(t *testing.T) {
+	f, cleanup := generateFlightrecord(t)
+	defer cleanup()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.AggregateSamples = true
+
+	converted, err := convert(t.Context(), receivertest.NewNopSettings(metadata.Type), cfg, f, sourceHistory{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, prof := range profilesOfType(converted.profiles, "wall") {
+		seen := make(map[uint64]bool)
+		for _, sample := range prof.Samples().All() {
+			key := sampleKey(sample.StackIndex(), sample.LinkIndex(), sample.AttributeIndices().AsRaw())
+			if seen[key] {
+				t.Fatal("expected samples with identical stack and attributes to be merged")
+			}
+			seen[key] = true
+		}
+	}
+}
+
+func TestConvertGroupBy(t *testing.T) {
+	tests := []struct {
+		groupBy string
+		check   func(t *testing.T, wall []pprofile.Profile)
+	}{
+		{
+			groupBy: groupByRangeName,
+			check: func(t *testing.T, wall []pprofile.Profile) {
+				if len(wall) == 0 {
+					t.Fatal("expected at least one wall profile")
+				}
+			},
+		},
+		{
+			groupBy: groupByFile,
+			check: func(t *testing.T, wall []pprofile.Profile) {
+				if len(wall) != 1 {
+					t.Fatalf("expected a single wall profile, got %d", len(wall))
+				}
+			},
+		},
+		{
+			groupBy: groupByWindow,
+			check: func(t *testing.T, wall []pprofile.Profile) {
+				for _, prof := range wall {
+					if prof.DurationNano() != uint64(time.Millisecond) {
+						t.Fatalf("expected a duration of 1ms, got %d ns", prof.DurationNano())
+					}
+					if uint64(prof.Time())%uint64(time.Millisecond) != 0 {
+						t.Fatalf("expected profile to start at a window boundary, got %s", prof.Time())
+					}
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			f, cleanup := generateFlightrecord(t)
+			defer cleanup()
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.GroupBy = tt.groupBy
+			cfg.Window = time.Millisecond
+
+			converted, err := convert(t.Context(), receivertest.NewNopSettings(metadata.Type), cfg, f, sourceHistory{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := converted.profiles
+
+			wall := profilesOfType(p, "wall")
+			tt.check(t, wall)
+
+			for _, prof := range wall {
+				start := uint64(prof.Time())
+				end := start + prof.DurationNano()
+				for _, sample := range prof.Samples().All() {
+					for _, ts := range sample.TimestampsUnixNano().All() {
+						if ts < start || ts >= end {
+							t.Fatalf("sample at %d is outside of profile range [%d, %d)", ts, start, end)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestConvertExcludeGCWorkers(t *testing.T) {
+	for _, exclude := range []bool{false, true} {
+		t.Run(strconv.FormatBool(exclude), func(t *testing.T) {
+			f, cleanup := generateFlightrecord(t)
+			defer cleanup()
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.ExcludeGCWorkers = exclude
+
+			converted, err := convert(t.Context(), receivertest.NewNopSettings(metadata.Type), cfg, f, sourceHistory{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := converted.profiles
+			dic := p.Dictionary()
+
+			gcWorkerSamples := 0
+			for _, prof := range profilesOfType(p, "wall") {
+				for _, sample := range prof.Samples().All() {
+					for _, idx := range sample.AttributeIndices().All() {
+						attr := dic.AttributeTable().At(int(idx))
+						if dic.StringTable().At(int(attr.KeyStrindex())) == attrGoroutineLabel &&
+							strings.HasPrefix(attr.Value().Str(), "GC (") {
+							gcWorkerSamples++
+						}
+					}
+				}
+			}
+			if exclude && gcWorkerSamples != 0 {
+				t.Fatalf("expected no samples of GC workers, got %d", gcWorkerSamples)
+			}
+			if !exclude && gcWorkerSamples == 0 {
+				t.Fatal("expected samples of labeled GC workers")
+			}
+		})
+	}
+}
+
+// allocMarker is a type of heap objects that exist before tracing starts. The
+// runtime only knows the type of objects with pointers that are larger than
+// 512 bytes.
+type allocMarker struct {
+	buf  [1024]byte
+	next *allocMarker
+}
+
+var allocMarkers *allocMarker
+
+func TestConvertAllocations(t *testing.T) {
+	// The runtime only emits events for allocations and frees of heap
+	// objects with this experiment enabled.
+	t.Setenv("GODEBUG", "traceallocfree=1")
+
+	allocMarkers = &allocMarker{next: &allocMarker{}}
+	defer func() { allocMarkers = nil }()
+
+	f, cleanup := generateFlightrecord(t)
+	defer cleanup()
+
+	converted, err := convert(t.Context(), receivertest.NewNopSettings(metadata.Type), createDefaultConfig().(*Config), f, sourceHistory{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := converted.profiles
+	dic := p.Dictionary()
+
+	for _, sampleType := range []string{"alloc_objects", "alloc_space", "inuse_objects", "inuse_space"} {
+		profs := profilesOfType(p, sampleType)
+		if len(profs) == 0 {
+			t.Fatalf("expected a %s profile", sampleType)
+		}
+		var total int64
+		typed, marked := false, false
+		for _, prof := range profs {
+			for _, sample := range prof.Samples().All() {
+				// The runtime records no stacks for allocations.
+				if sample.StackIndex() != 0 {
+					t.Fatalf("expected samples without a stack in %s profile", sampleType)
+				}
+				// In-use samples describe the heap at the end of the trace.
+				if strings.HasPrefix(sampleType, "inuse_") {
+					for _, ts := range sample.TimestampsUnixNano().All() {
+						if want := uint64(converted.end.UnixNano()); ts != want {
+							t.Fatalf("expected %s samples at the end of the trace %d, got %d", sampleType, want, ts)
+						}
+					}
+				}
+				for _, v := range sample.Values().All() {
+					total += v
+				}
+				for _, idx := range sample.AttributeIndices().All() {
+					attr := dic.AttributeTable().At(int(idx))
+					if dic.StringTable().At(int(attr.KeyStrindex())) == attrHeapObjectType {
+						typed = true
+						marked = marked || strings.Contains(attr.Value().Str(), "allocMarker")
+					}
+				}
+			}
+		}
+		if total <= 0 {
+			t.Fatalf("expected positive values in %s profile, got %d", sampleType, total)
+		}
+		if !typed {
+			t.Fatalf("expected samples with %s in %s profile", attrHeapObjectType, sampleType)
+		}
+		// Objects that existed before tracing started are in use, but were
+		// not allocated within the trace.
+		if inUse := strings.HasPrefix(sampleType, "inuse_"); marked != inUse {
+			t.Fatalf("expected objects allocated before the trace in %s profile: %t, got %t", sampleType, inUse, marked)
+		}
+	}
+}
+
+func TestConvertLinks(t *testing.T) {
+	tests := []struct {
+		name   string
+		links  LinksConfig
+		linked bool
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:   "key_value",
+			links:  LinksConfig{Extractor: linkExtractorKeyValue},
+			linked: true,
+		},
+		{
+			name: "regex",
+			links: LinksConfig{
+				Extractor: linkExtractorRegex,
+				Pattern:   `trace_id=(?P<trace_id>\w+) span_id=(?P<span_id>\w+)`,
+			},
+			linked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, cleanup := generateFlightrecord(t)
+			defer cleanup()
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.Links = tt.links
+
+			converted, err := convert(t.Context(), receivertest.NewNopSettings(metadata.Type), cfg, f, sourceHistory{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := converted.profiles
+			dic := p.Dictionary()
+
+			linkedSamples := 0
+			for _, prof := range profilesOfType(p, "wall") {
+				for _, sample := range prof.Samples().All() {
+					if sample.LinkIndex() == 0 {
+						continue
+					}
+					linkedSamples++
+					link := dic.LinkTable().At(int(sample.LinkIndex()))
+					if got := link.TraceID().String(); got != testTraceID {
+						t.Fatalf("expected trace ID %s, got %s", testTraceID, got)
+					}
+					if got := link.SpanID().String(); got != testSpanID {
+						t.Fatalf("expected span ID %s, got %s", testSpanID, got)
+					}
+				}
+			}
+			if tt.linked && linkedSamples == 0 {
+				t.Fatal("expected samples linked to the distributed trace")
+			}
+			if !tt.linked && linkedSamples != 0 {
+				t.Fatalf("expected no linked samples, got %d", linkedSamples)
+			}
+		})
+	}
+}
+
+func TestConvertSemconvMetricNames(t *testing.T) {
+	for _, semconvNames := range []bool{false, true} {
+		t.Run(strconv.FormatBool(semconvNames), func(t *testing.T) {
+			f, cleanup := generateFlightrecord(t)
+			defer cleanup()
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.SemconvMetricNames = semconvNames
+
+			converted, err := convert(t.Context(), receivertest.NewNopSettings(metadata.Type), cfg, f, sourceHistory{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			units := make(map[string]string)
+			metrics := make(map[string]pmetric.Metric)
+			for _, metric := range converted.metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+				units[metric.Name()] = metric.Unit()
+				metrics[metric.Name()] = metric
+			}
+
+			want := map[string]string{
+				"/gc/heap/goal":     "bytes",
+				"/sched/gomaxprocs": "threads",
+			}
+			if semconvNames {
+				want = map[string]string{
+					"go.memory.gc.goal":  "By",
+					"go.processor.limit": "{thread}",
+					"go.goroutine.count": "{goroutine}",
+				}
+				// The semantic conventions define UpDownCounters.
+				for name := range want {
+					metric := metrics[name]
+					if metric.Type() != pmetric.MetricTypeSum || metric.Sum().IsMonotonic() ||
+						metric.Sum().AggregationTemporality() != pmetric.AggregationTemporalityCumulative {
+						t.Fatalf("expected metric %s to be a non-monotonic cumulative sum", name)
+					}
+				}
+				// generateFlightrecord waits for its goroutines in the test.
+				if count := metrics["go.goroutine.count"].Sum().DataPoints().At(0).IntValue(); count <= 0 {
+					t.Fatalf("expected goroutines to exist, got %d", count)
+				}
+			} else if _, ok := units["go.goroutine.count"]; ok {
+				t.Fatal("expected no go.goroutine.count without semconv_metric_names")
+			}
+			// Metrics without an equivalent keep their name.
+			want["/memory/classes/heap/objects"] = "bytes"
+			for name, unit := range want {
+				got, ok := units[name]
+				if !ok {
+					t.Fatalf("expected metric %s, got %v", name, units)
+				}
+				if got != unit {
+					t.Fatalf("expected unit %q for metric %s, got %q", unit, name, got)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertMetricsConfig(t *testing.T) {
+	f, cleanup := generateFlightrecord(t)
+	defer cleanup()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Metrics.GoGcMmu.Enabled = false
+	cfg.Histograms.GoGcStwDuration.Enabled = false
+
+	converted, err := convert(t.Context(), receivertest.NewNopSettings(metadata.Type), cfg, f, sourceHistory{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make(map[string]bool)
+	for _, metric := range converted.metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+		names[metric.Name()] = true
+	}
+	for _, name := range []string{metadata.MetricsInfo.GoGcMmu.Name, metricGCSTWDuration} {
+		if names[name] {
+			t.Fatalf("expected disabled metric %s to be dropped", name)
+		}
+	}
+	if !names[metadata.MetricsInfo.GoProcUtilization.Name] {
+		t.Fatalf("expected enabled metric %s, got %v", metadata.MetricsInfo.GoProcUtilization.Name, names)
+	}
+}
+
+func TestConvertRuntimeMetricsFilter(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		filter  RuntimeMetricsConfig
+		want    []string
+		dropped []string
+	}{
+		{
+			name:   "no filter",
+			filter: RuntimeMetricsConfig{},
+			want:   []string{"/gc/heap/goal", "/sched/gomaxprocs", "/memory/classes/heap/objects"},
+		},
+		{
+			name:    "include",
+			filter:  RuntimeMetricsConfig{Include: []string{`^/gc/`}},
+			want:    []string{"/gc/heap/goal"},
+			dropped: []string{"/sched/gomaxprocs", "/memory/classes/heap/objects"},
+		},
+		{
+			name:    "exclude",
+			filter:  RuntimeMetricsConfig{Exclude: []string{`:bytes$`}},
+			want:    []string{"/sched/gomaxprocs"},
+			dropped: []string{"/gc/heap/goal", "/memory/classes/heap/objects"},
+		},
+		{
+			name:    "include and exclude",
+			filter:  RuntimeMetricsConfig{Include: []string{`:bytes$`}, Exclude: []string{`^/gc/`}},
+			want:    []string{"/memory/classes/heap/objects"},
+			dropped: []string{"/gc/heap/goal", "/sched/gomaxprocs"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f, cleanup := generateFlightrecord(t)
+			defer cleanup()
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.RuntimeMetrics = tc.filter
+
+			converted, err := convert(t.Context(), receivertest.NewNopSettings(metadata.Type), cfg, f, sourceHistory{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := make(map[string]bool)
+			for _, metric := range converted.metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+				names[metric.Name()] = true
+			}
+			for _, name := range tc.want {
+				if !names[name] {
+					t.Fatalf("expected metric %s, got %v", name, names)
+				}
+			}
+			for _, name := range tc.dropped {
+				if names[name] {
+					t.Fatalf("expected metric %s to be filtered", name)
 				}
 			}
+			// Filtering runtime metrics does not affect derived metrics.
+			if !names[metadata.MetricsInfo.GoProcUtilization.Name] {
+				t.Fatalf("expected metric %s", metadata.MetricsInfo.GoProcUtilization.Name)
+			}
+		})
+	}
+}
+
+func TestConvertRuntimeMetricTypes(t *testing.T) {
+	f, cleanup := generateFlightrecord(t)
+	defer cleanup()
+
+	converted, err := convert(t.Context(), receivertest.NewNopSettings(metadata.Type), createDefaultConfig().(*Config), f, sourceHistory{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gomaxprocs pmetric.Metric
+	for _, metric := range converted.metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+		if metric.Name() == "/sched/gomaxprocs" {
+			gomaxprocs = metric
+		}
+	}
+	if gomaxprocs.Type() != pmetric.MetricTypeGauge {
+		t.Fatalf("expected /sched/gomaxprocs to be a gauge, got %s", gomaxprocs.Type())
+	}
+	for _, dp := range gomaxprocs.Gauge().DataPoints().All() {
+		if dp.ValueType() != pmetric.NumberDataPointValueTypeInt {
+			t.Fatalf("expected int data points, got %s", dp.ValueType())
+		}
+		if dp.IntValue() != int64(runtime.GOMAXPROCS(0)) {
+			t.Fatalf("expected GOMAXPROCS %d, got %d", runtime.GOMAXPROCS(0), dp.IntValue())
+		}
+	}
+}
+
+func TestRuntimeMetricCumulative(t *testing.T) {
+	// Flight records do not hold cumulative metrics yet.
+	const name = "/gc/heap/allocs:bytes"
+	start := time.Unix(100, 0)
+	ts := start.Add(time.Second)
+
+	metric := pmetric.NewMetric()
+	initRuntimeMetric(metric, name, false)
+	appendRuntimeMetricDataPoint(metric, exptrace.Metric{Name: name, Value: exptrace.Uint64Value(42)}, ts, start)
+
+	if metric.Type() != pmetric.MetricTypeSum {
+		t.Fatalf("expected a sum, got %s", metric.Type())
+	}
+	sum := metric.Sum()
+	if !sum.IsMonotonic() || sum.AggregationTemporality() != pmetric.AggregationTemporalityCumulative {
+		t.Fatal("expected a monotonic cumulative sum")
+	}
+	dp := sum.DataPoints().At(0)
+	if !dp.StartTimestamp().AsTime().Equal(start) || !dp.Timestamp().AsTime().Equal(ts) {
+		t.Fatalf("expected data point covering [%s, %s], got [%s, %s]",
+			start, ts, dp.StartTimestamp().AsTime(), dp.Timestamp().AsTime())
+	}
+	if dp.IntValue() != 42 {
+		t.Fatalf("expected value 42, got %d", dp.IntValue())
+	}
+}
+
+func TestProfileTimelineCovering(t *testing.T) {
+	at := func(ns int64) time.Time { return time.Unix(0, ns) }
+	long := &profileState{sampleType: "wall", startTS: at(0), endTS: at(100)}
+	short := &profileState{sampleType: "wall", startTS: at(40), endTS: at(60)}
+	late := &profileState{sampleType: "wall", startTS: at(80), endTS: at(90)}
+	groups := &profileGroups{order: []*profileState{late, long, short}}
+	timelines := groups.timelines()
+	if len(timelines) != 1 {
+		t.Fatalf("expected a timeline per sample type, got %d", len(timelines))
+	}
+
+	for _, tc := range []struct {
+		ts   int64
+		want *profileState
+	}{
+		{ts: 10, want: long},
+		{ts: 50, want: short},
+		{ts: 60, want: short},
+		{ts: 70, want: long},
+		{ts: 85, want: late},
+		{ts: 101, want: nil},
+	} {
+		if got := timelines[0].covering(at(tc.ts)); got != tc.want {
+			t.Fatalf("expected profile %p to cover %d, got %p", tc.want, tc.ts, got)
 		}
+	}
+}
+
+func TestDownsampleRuntimeMetric(t *testing.T) {
+	const name = "/gc/heap/goal:bytes"
+	start := time.Unix(100, 0)
+	// Two data points in the first second and three in the second.
+	points := []struct {
+		offset time.Duration
+		value  uint64
+	}{
+		{100 * time.Millisecond, 4},
+		{900 * time.Millisecond, 2},
+		{1100 * time.Millisecond, 1},
+		{1500 * time.Millisecond, 9},
+		{1900 * time.Millisecond, 5},
+	}
+
+	for _, tc := range []struct {
+		name        string
+		resolution  time.Duration
+		aggregation string
+		summary     bool
+		want        []float64
+	}{
+		{name: "disabled", aggregation: metricsAggregationLast, want: []float64{4, 2, 1, 9, 5}},
+		{name: "last", resolution: time.Second, aggregation: metricsAggregationLast, want: []float64{2, 5}},
+		{name: "min", resolution: time.Second, aggregation: metricsAggregationMin, want: []float64{2, 1}},
+		{name: "max", resolution: time.Second, aggregation: metricsAggregationMax, want: []float64{4, 9}},
+		{name: "avg", resolution: time.Second, aggregation: metricsAggregationAvg, want: []float64{3, 5}},
+		{name: "summary", aggregation: metricsAggregationMax, summary: true, want: []float64{9}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			metric := pmetric.NewMetric()
+			initRuntimeMetric(metric, name, false)
+			for _, p := range points {
+				appendRuntimeMetricDataPoint(metric, exptrace.Metric{Name: name, Value: exptrace.Uint64Value(p.value)}, start.Add(p.offset), start)
+			}
+			downsampleRuntimeMetric(metric, tc.resolution, tc.aggregation, tc.summary)
+
+			dps := metric.Gauge().DataPoints()
+			if dps.Len() != len(tc.want) {
+				t.Fatalf("expected %d data points, got %d", len(tc.want), dps.Len())
+			}
+			for i, dp := range dps.All() {
+				if got := numberValue(dp); got != tc.want[i] {
+					t.Fatalf("expected value %v at %d, got %v", tc.want[i], i, got)
+				}
+			}
+			// Aggregates have the timestamp of the last data point of the
+			// window.
+			last := dps.At(dps.Len() - 1).Timestamp().AsTime()
+			if !last.Equal(start.Add(points[len(points)-1].offset)) {
+				t.Fatalf("expected last data point at %s, got %s", start.Add(points[len(points)-1].offset), last)
+			}
+		})
+	}
+}
+
+func TestThreadStats(t *testing.T) {
+	threads := newThreadStats()
+	// P 0 starts on thread 1, stops and starts again on thread 1 and is
+	// then handed off to thread 2.
+	threads.handleTransition(exptrace.MakeProcStateTransition(0, exptrace.ProcIdle, exptrace.ProcRunning), 1)
+	threads.handleTransition(exptrace.MakeProcStateTransition(0, exptrace.ProcIdle, exptrace.ProcRunning), 1)
+	threads.handleTransition(exptrace.MakeProcStateTransition(0, exptrace.ProcIdle, exptrace.ProcRunning), 2)
+	if threads.handoffs != 1 {
+		t.Fatalf("expected 1 handoff, got %d", threads.handoffs)
+	}
+
+	// Two goroutines enter system calls at once, then one of them leaves and
+	// another one enters.
+	threads.handleTransition(exptrace.MakeGoStateTransition(1, exptrace.GoRunning, exptrace.GoSyscall), 1)
+	threads.handleTransition(exptrace.MakeGoStateTransition(2, exptrace.GoRunning, exptrace.GoSyscall), 2)
+	threads.handleTransition(exptrace.MakeGoStateTransition(1, exptrace.GoSyscall, exptrace.GoRunning), 1)
+	threads.handleTransition(exptrace.MakeGoStateTransition(3, exptrace.GoRunning, exptrace.GoSyscall), 3)
+	if threads.syscalls != 2 || threads.peakSyscalls != 2 {
+		t.Fatalf("expected 2 threads in system calls at most, got %d now and %d at most", threads.syscalls, threads.peakSyscalls)
+	}
+}
+
+func TestConvertAnomalies(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "flightrecord-*.out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := trace.Start(f); err != nil {
+		t.Fatal(err)
+	}
+	// Block a goroutine on a mutex and another one on a WaitGroup for a
+	// while. Both wait with the reason "sync".
+	var mu sync.Mutex
+	mu.Lock()
+	var pending sync.WaitGroup
+	pending.Add(1)
+	started := make(chan struct{}, 2)
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		started <- struct{}{}
+		mu.Lock()
+		defer mu.Unlock()
 	})
+	wg.Go(func() {
+		started <- struct{}{}
+		pending.Wait()
+	})
+	<-started
+	<-started
+	time.Sleep(20 * time.Millisecond)
+	mu.Unlock()
+	pending.Done()
+	wg.Wait()
+	runtime.GC()
+	trace.Stop()
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Anomalies = AnomaliesConfig{
+		STWPause:     time.Nanosecond,
+		SchedLatency: time.Nanosecond,
+		MutexBlock:   10 * time.Millisecond,
+	}
+	converted, err := convert(t.Context(), receivertest.NewNopSettings(metadata.Type), cfg, f, sourceHistory{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	anomalies := make(map[string]int)
+	for _, record := range converted.logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().All() {
+		if !strings.HasPrefix(record.EventName(), "go.anomaly.") {
+			continue
+		}
+		anomalies[record.EventName()]++
+		for _, key := range []string{attrAnomalyWindowStart, attrAnomalyWindowEnd, attrAnomalyDuration, attrAnomalyThreshold} {
+			if _, ok := record.Attributes().Get(key); !ok {
+				t.Fatalf("expected attribute %s on %s, got %v", key, record.EventName(), record.Attributes().AsRaw())
+			}
+		}
+		duration, _ := record.Attributes().Get(attrAnomalyDuration)
+		threshold, _ := record.Attributes().Get(attrAnomalyThreshold)
+		if duration.Double() < threshold.Double() {
+			t.Fatalf("expected %s to exceed its threshold, got %f < %f", record.EventName(), duration.Double(), threshold.Double())
+		}
+		if record.EventName() == anomalyMutexBlock {
+			if _, ok := record.Attributes().Get(attrGoID); !ok {
+				t.Fatal("expected the blocked goroutine")
+			}
+			stack, _ := record.Attributes().Get("code.stacktrace")
+			if !strings.Contains(stack.Str(), "sync.(*Mutex).Lock") {
+				t.Fatalf("expected only goroutines blocked on a mutex, got stack %q", stack.Str())
+			}
+		}
+	}
+	for _, name := range []string{anomalySTWPause, anomalySchedLatency, anomalyMutexBlock} {
+		if anomalies[name] == 0 {
+			t.Fatalf("expected anomaly %s, got %v", name, anomalies)
+		}
+	}
 }
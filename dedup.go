@@ -0,0 +1,289 @@
+package flightrecorderreceiver
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+)
+
+// sourceHistory describes the data emitted before for the source of a flight
+// record.
+type sourceHistory struct {
+	// start is the start of the first flight record of the source. It is
+	// zero if no flight record of the source was emitted.
+	start time.Time
+	// end is the end of the latest flight record of the source. Spans, log
+	// records and the counts of the derived delta sums up to it were already
+	// emitted. It is zero if no flight record of the source was emitted.
+	end time.Time
+}
+
+// emitted reports whether data at ts was already emitted for an earlier
+// flight record of the source.
+func (h sourceHistory) emitted(ts time.Time) bool {
+	return !ts.After(h.end)
+}
+
+// unemitted returns the part of the time between from and to that was not
+// emitted for an earlier flight record of the source.
+func (h sourceHistory) unemitted(from, to time.Time) time.Duration {
+	if from.Before(h.end) {
+		from = h.end
+	}
+	return max(to.Sub(from), 0)
+}
+
+// cumulativeStart returns the start timestamp of the cumulative sums of a
+// flight record that starts at first. Cumulative sums keep the start of the
+// first flight record of the source, as their values accumulate across flight
+// records.
+func (h sourceHistory) cumulativeStart(first time.Time) time.Time {
+	if !h.start.IsZero() && h.start.Before(first) {
+		return h.start
+	}
+	return first
+}
+
+// sourceState holds the timestamp of the most recent data emitted for a
+// source.
+type sourceState struct {
+	history sourceHistory
+	// metrics maps metric name to the timestamp of its most recent data
+	// point.
+	metrics map[string]pcommon.Timestamp
+	// profiles maps sample type to the timestamp of its most recent sample.
+	profiles map[string]uint64
+}
+
+// deduplicator drops data of flight records that was already emitted for an
+// earlier flight record of the same source. A flight recorder keeps a sliding
+// window, so consecutive flight records of a process overlap in time, and a
+// file that is scraped again holds the same data as before.
+type deduplicator struct {
+	mu sync.Mutex
+
+	sources map[string]*sourceState
+	// seen holds the sources of the current scrape.
+	seen map[string]bool
+}
+
+// newDeduplicator returns nil if deduplication is disabled.
+func newDeduplicator(cfg DeduplicationConfig) *deduplicator {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &deduplicator{
+		sources: make(map[string]*sourceState),
+		seen:    make(map[string]bool),
+	}
+}
+
+// sourcePattern compiles the source pattern of cfg. It returns nil if there
+// is none.
+func sourcePattern(cfg DeduplicationConfig) (*regexp.Regexp, error) {
+	if cfg.SourcePattern == "" {
+		return nil, nil
+	}
+	pattern, err := regexp.Compile(cfg.SourcePattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deduplication source_pattern: %w", err)
+	}
+	return pattern, nil
+}
+
+// flightRecordSource returns the identity of the source of the flight record
+// at path. It is the first group of pattern, or its whole match if it has no
+// groups. Without a matching pattern the path identifies the source.
+func flightRecordSource(pattern *regexp.Regexp, path string) string {
+	if pattern == nil {
+		return path
+	}
+	m := pattern.FindStringSubmatch(path)
+	switch {
+	case m == nil:
+		return path
+	case len(m) > 1:
+		return m[1]
+	default:
+		return m[0]
+	}
+}
+
+// history returns the history of source. It is empty if d is nil.
+func (d *deduplicator) history(source string) sourceHistory {
+	if d == nil {
+		return sourceHistory{}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if state, ok := d.sources[source]; ok {
+		return state.history
+	}
+	return sourceHistory{}
+}
+
+// deduplicate drops the data points and samples of s that are not newer than
+// the data emitted before for source, and the exemplars that refer to
+// dropped profiles.
+func (d *deduplicator) deduplicate(source string, s signals) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.seen[source] = true
+	state, ok := d.sources[source]
+	if !ok {
+		state = &sourceState{
+			metrics:  make(map[string]pcommon.Timestamp),
+			profiles: make(map[string]uint64),
+		}
+		d.sources[source] = state
+	}
+	state.deduplicateProfiles(s.profiles)
+	state.deduplicateMetrics(s.metrics)
+	removeDanglingExemplars(s.metrics, s.profiles)
+	if state.history.start.IsZero() {
+		state.history.start = s.start
+	}
+	if s.end.After(state.history.end) {
+		state.history.end = s.end
+	}
+}
+
+// finishScrape forgets the sources that had no flight record in the current
+// scrape, e.g. because their files were removed.
+func (d *deduplicator) finishScrape() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for source := range d.sources {
+		if !d.seen[source] {
+			delete(d.sources, source)
+		}
+	}
+	clear(d.seen)
+}
+
+func (st *sourceState) deduplicateMetrics(metrics pmetric.Metrics) {
+	// Data points of a flight record are only compared against earlier
+	// flight records, so the new timestamps are applied at the end.
+	latest := make(map[string]pcommon.Timestamp)
+	keep := func(name string, ts pcommon.Timestamp) bool {
+		if last, ok := st.metrics[name]; ok && ts <= last {
+			return false
+		}
+		latest[name] = max(latest[name], ts)
+		return true
+	}
+
+	metrics.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			sm.Metrics().RemoveIf(func(metric pmetric.Metric) bool {
+				name := metric.Name()
+				switch metric.Type() {
+				case pmetric.MetricTypeGauge:
+					dps := metric.Gauge().DataPoints()
+					dps.RemoveIf(func(dp pmetric.NumberDataPoint) bool { return !keep(name, dp.Timestamp()) })
+					return dps.Len() == 0
+				case pmetric.MetricTypeSum:
+					dps := metric.Sum().DataPoints()
+					dps.RemoveIf(func(dp pmetric.NumberDataPoint) bool { return !keep(name, dp.Timestamp()) })
+					return dps.Len() == 0
+				case pmetric.MetricTypeHistogram:
+					dps := metric.Histogram().DataPoints()
+					dps.RemoveIf(func(dp pmetric.HistogramDataPoint) bool { return !keep(name, dp.Timestamp()) })
+					return dps.Len() == 0
+				}
+				return false
+			})
+			return sm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+	for name, ts := range latest {
+		st.metrics[name] = ts
+	}
+}
+
+func (st *sourceState) deduplicateProfiles(profiles pprofile.Profiles) {
+	stringTable := profiles.Dictionary().StringTable()
+	latest := make(map[string]uint64)
+
+	profiles.ResourceProfiles().RemoveIf(func(rp pprofile.ResourceProfiles) bool {
+		rp.ScopeProfiles().RemoveIf(func(sp pprofile.ScopeProfiles) bool {
+			sp.Profiles().RemoveIf(func(p pprofile.Profile) bool {
+				sampleType := stringTable.At(int(p.SampleType().TypeStrindex()))
+				last, seen := st.profiles[sampleType]
+				p.Samples().RemoveIf(func(sample pprofile.Sample) bool {
+					timestamps := sample.TimestampsUnixNano()
+					values := sample.Values()
+					var keptTimestamps []uint64
+					var keptValues []int64
+					for i := 0; i < timestamps.Len(); i++ {
+						ts := timestamps.At(i)
+						if seen && ts <= last {
+							continue
+						}
+						latest[sampleType] = max(latest[sampleType], ts)
+						keptTimestamps = append(keptTimestamps, ts)
+						if i < values.Len() {
+							keptValues = append(keptValues, values.At(i))
+						}
+					}
+					timestamps.FromRaw(keptTimestamps)
+					values.FromRaw(keptValues)
+					return timestamps.Len() == 0
+				})
+				if seen && p.Samples().Len() > 0 && uint64(p.Time()) <= last {
+					// The profile now starts after the data emitted before.
+					end := uint64(p.Time()) + p.DurationNano()
+					p.SetTime(pcommon.Timestamp(last + 1))
+					p.SetDurationNano(end - last - 1)
+				}
+				return p.Samples().Len() == 0
+			})
+			return sp.Profiles().Len() == 0
+		})
+		return rp.ScopeProfiles().Len() == 0
+	})
+	for sampleType, ts := range latest {
+		st.profiles[sampleType] = ts
+	}
+}
+
+// removeDanglingExemplars removes the exemplars of metrics that refer to a
+// profile that is not part of profiles.
+func removeDanglingExemplars(metrics pmetric.Metrics, profiles pprofile.Profiles) {
+	profileIDs := make(map[string]bool)
+	for _, rp := range profiles.ResourceProfiles().All() {
+		for _, sp := range rp.ScopeProfiles().All() {
+			for _, p := range sp.Profiles().All() {
+				profileIDs[p.ProfileID().String()] = true
+			}
+		}
+	}
+	dangling := func(exemplar pmetric.Exemplar) bool {
+		id, ok := exemplar.FilteredAttributes().Get(attrProfileID)
+		return ok && !profileIDs[id.Str()]
+	}
+	for _, rm := range metrics.ResourceMetrics().All() {
+		for _, sm := range rm.ScopeMetrics().All() {
+			for _, metric := range sm.Metrics().All() {
+				var dps pmetric.NumberDataPointSlice
+				switch metric.Type() {
+				case pmetric.MetricTypeGauge:
+					dps = metric.Gauge().DataPoints()
+				case pmetric.MetricTypeSum:
+					dps = metric.Sum().DataPoints()
+				default:
+					continue
+				}
+				for _, dp := range dps.All() {
+					dp.Exemplars().RemoveIf(dangling)
+				}
+			}
+		}
+	}
+}
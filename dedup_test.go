@@ -0,0 +1,190 @@
+package flightrecorderreceiver
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/florianl/flightrecorderreceiver/internal/metadata"
+)
+
+func TestDeduplicate(t *testing.T) {
+	f, cleanup := generateFlightrecord(t)
+	defer cleanup()
+
+	dedup := newDeduplicator(DeduplicationConfig{Enabled: true})
+	convertRecord := func(source string) signals {
+		t.Helper()
+		if _, err := f.(io.Seeker).Seek(0, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		converted, err := convert(t.Context(), receivertest.NewNopSettings(metadata.Type), createDefaultConfig().(*Config), f, dedup.history(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return converted
+	}
+
+	first := convertRecord("/tmp/flightrecord.out")
+	dataPoints, samples := first.metrics.DataPointCount(), first.profiles.SampleCount()
+	spans, records := first.traces.SpanCount(), first.logs.LogRecordCount()
+	events := traceEvents(first)
+	dedup.deduplicate("/tmp/flightrecord.out", first)
+	if got := first.metrics.DataPointCount(); got != dataPoints {
+		t.Fatalf("expected all %d data points of the first flight record, got %d", dataPoints, got)
+	}
+	if got := first.profiles.SampleCount(); got != samples {
+		t.Fatalf("expected all %d samples of the first flight record, got %d", samples, got)
+	}
+	dedup.finishScrape()
+	if got := dedup.history("/tmp/flightrecord.out"); !got.start.Equal(first.start) || !got.end.Equal(first.end) {
+		t.Fatalf("expected the history to cover [%s, %s], got [%s, %s]", first.start, first.end, got.start, got.end)
+	}
+
+	// Scraping the same file again emits nothing.
+	second := convertRecord("/tmp/flightrecord.out")
+	if got := second.traces.SpanCount(); got != 0 {
+		t.Fatalf("expected no spans of the same flight record, got %d", got)
+	}
+	if got := second.logs.LogRecordCount(); got != 0 {
+		t.Fatalf("expected no log records of the same flight record, got %d", got)
+	}
+	// Only the events before the clock synchronization, whose wall time is
+	// unknown, are counted again.
+	if got := traceEvents(second); got >= events {
+		t.Fatalf("expected less than %d events after the end of the same flight record, got %d", events, got)
+	}
+	dedup.deduplicate("/tmp/flightrecord.out", second)
+	if got := second.metrics.DataPointCount(); got != 0 {
+		t.Fatalf("expected no data points of the same flight record, got %d", got)
+	}
+	if got := second.profiles.SampleCount(); got != 0 {
+		t.Fatalf("expected no samples of the same flight record, got %d", got)
+	}
+	dedup.finishScrape()
+
+	// Flight records of other sources are not affected.
+	other := convertRecord("/tmp/other.out")
+	if got := other.traces.SpanCount(); got != spans {
+		t.Fatalf("expected all %d spans of another source, got %d", spans, got)
+	}
+	// The same task or region has the same IDs whenever it is converted.
+	firstSpans := first.traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i, span := range other.traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().All() {
+		if span.TraceID() != firstSpans.At(i).TraceID() || span.SpanID() != firstSpans.At(i).SpanID() {
+			t.Fatalf("expected span %s to keep its IDs, got %s/%s", span.Name(), span.TraceID(), span.SpanID())
+		}
+	}
+	if got := other.logs.LogRecordCount(); got != records {
+		t.Fatalf("expected all %d log records of another source, got %d", records, got)
+	}
+	dedup.deduplicate("/tmp/other.out", other)
+	if got := other.metrics.DataPointCount(); got != dataPoints {
+		t.Fatalf("expected all %d data points of another source, got %d", dataPoints, got)
+	}
+	dedup.finishScrape()
+
+	// Sources without a flight record in a scrape are forgotten.
+	if _, ok := dedup.sources["/tmp/flightrecord.out"]; ok {
+		t.Fatal("expected source without a flight record to be forgotten")
+	}
+}
+
+func TestSourceHistoryCumulativeStart(t *testing.T) {
+	first := time.Unix(100, 0)
+	for _, tc := range []struct {
+		name    string
+		history sourceHistory
+		want    time.Time
+	}{
+		{name: "no history", want: first},
+		{name: "earlier flight record", history: sourceHistory{start: time.Unix(50, 0)}, want: time.Unix(50, 0)},
+		{name: "later flight record", history: sourceHistory{start: time.Unix(150, 0)}, want: first},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.history.cumulativeStart(first); !got.Equal(tc.want) {
+				t.Fatalf("expected start %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSourceHistoryUnemitted(t *testing.T) {
+	from, to := time.Unix(100, 0), time.Unix(110, 0)
+	for _, tc := range []struct {
+		name    string
+		history sourceHistory
+		want    time.Duration
+	}{
+		{name: "no history", want: 10 * time.Second},
+		{name: "overlapping flight record", history: sourceHistory{end: time.Unix(104, 0)}, want: 6 * time.Second},
+		{name: "later flight record", history: sourceHistory{end: time.Unix(120, 0)}, want: 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.history.unemitted(from, to); got != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+			if got, want := tc.history.emitted(to), tc.want == 0; got != want {
+				t.Fatalf("expected emitted %t, got %t", want, got)
+			}
+		})
+	}
+}
+
+// traceEvents returns the value of flightrecorder.trace.events in s.
+func traceEvents(s signals) int64 {
+	for _, metric := range s.metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+		if metric.Name() == metadata.MetricsInfo.FlightrecorderTraceEvents.Name {
+			return metric.Sum().DataPoints().At(0).IntValue()
+		}
+	}
+	return 0
+}
+
+func TestRemoveDanglingExemplars(t *testing.T) {
+	profiles := pprofile.NewProfiles()
+	kept := profiles.ResourceProfiles().AppendEmpty().ScopeProfiles().AppendEmpty().Profiles().AppendEmpty()
+	kept.SetProfileID(pprofile.ProfileID{1})
+
+	metrics := pmetric.NewMetrics()
+	dp := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty()
+	for _, id := range []pprofile.ProfileID{{1}, {2}} {
+		dp.Exemplars().AppendEmpty().FilteredAttributes().PutStr(attrProfileID, id.String())
+	}
+
+	removeDanglingExemplars(metrics, profiles)
+	if dp.Exemplars().Len() != 1 {
+		t.Fatalf("expected 1 exemplar, got %d", dp.Exemplars().Len())
+	}
+	if id, _ := dp.Exemplars().At(0).FilteredAttributes().Get(attrProfileID); id.Str() != kept.ProfileID().String() {
+		t.Fatalf("expected exemplar of profile %s, got %s", kept.ProfileID(), id.Str())
+	}
+}
+
+func TestFlightRecordSource(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		pattern string
+		path    string
+		want    string
+	}{
+		{name: "no pattern", path: "/tmp/app-42-1.out", want: "/tmp/app-42-1.out"},
+		{name: "group", pattern: `app-(\d+)-`, path: "/tmp/app-42-1.out", want: "42"},
+		{name: "whole match", pattern: `app-\d+`, path: "/tmp/app-42-1.out", want: "app-42"},
+		{name: "no match", pattern: `svc-(\d+)`, path: "/tmp/app-42-1.out", want: "/tmp/app-42-1.out"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pattern, err := sourcePattern(DeduplicationConfig{SourcePattern: tc.pattern})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := flightRecordSource(pattern, tc.path); got != tc.want {
+				t.Fatalf("expected source %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
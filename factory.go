@@ -2,6 +2,7 @@ package flightrecorderreceiver
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
@@ -9,6 +10,8 @@ import (
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/xreceiver"
 	"go.opentelemetry.io/collector/scraper/scraperhelper"
+
+	"github.com/florianl/flightrecorderreceiver/internal/metadata"
 )
 
 var compType = component.MustNewType("flightrecorder")
@@ -19,12 +22,27 @@ func NewFactory() receiver.Factory {
 		createDefaultConfig,
 		xreceiver.WithProfiles(createProfilesReceiver, component.StabilityLevelDevelopment),
 		xreceiver.WithMetrics(createMetricsReceiver, component.StabilityLevelDevelopment),
+		xreceiver.WithTraces(createTracesReceiver, component.StabilityLevelDevelopment),
+		xreceiver.WithLogs(createLogsReceiver, component.StabilityLevelDevelopment),
 	)
 }
 
 func createDefaultConfig() component.Config {
 	return &Config{
-		ControllerConfig: scraperhelper.NewDefaultControllerConfig(),
+		ControllerConfig:   scraperhelper.NewDefaultControllerConfig(),
+		GroupBy:            groupByGoroutine,
+		Window:             time.Second,
+		MMUWindows:         []time.Duration{time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond},
+		CensusResolution:   100 * time.Millisecond,
+		MetricsAggregation: metricsAggregationLast,
+		Deduplication: DeduplicationConfig{
+			Enabled: true,
+		},
+
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Histograms: HistogramsConfig{
+			GoGcStwDuration: metadata.MetricConfig{Enabled: true},
+		},
 	}
 }
 
@@ -37,7 +55,10 @@ func createProfilesReceiver(
 	c := cfg.(*Config)
 
 	// Get or create the shared receiver instance
-	rcv := c.getOrCreateReceiver(settings.TelemetrySettings)
+	rcv, err := c.getOrCreateReceiver(settings)
+	if err != nil {
+		return nil, err
+	}
 	rcv.profilesConsumer = consumer
 
 	return rcv, nil
@@ -52,8 +73,47 @@ func createMetricsReceiver(
 	c := cfg.(*Config)
 
 	// Get or create the shared receiver instance
-	rcv := c.getOrCreateReceiver(settings.TelemetrySettings)
+	rcv, err := c.getOrCreateReceiver(settings)
+	if err != nil {
+		return nil, err
+	}
 	rcv.metricsConsumer = consumer
 
 	return rcv, nil
 }
+
+func createTracesReceiver(
+	_ context.Context,
+	settings receiver.Settings,
+	cfg component.Config,
+	consumer consumer.Traces,
+) (receiver.Traces, error) {
+	c := cfg.(*Config)
+
+	// Get or create the shared receiver instance
+	rcv, err := c.getOrCreateReceiver(settings)
+	if err != nil {
+		return nil, err
+	}
+	rcv.tracesConsumer = consumer
+
+	return rcv, nil
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	settings receiver.Settings,
+	cfg component.Config,
+	consumer consumer.Logs,
+) (receiver.Logs, error) {
+	c := cfg.(*Config)
+
+	// Get or create the shared receiver instance
+	rcv, err := c.getOrCreateReceiver(settings)
+	if err != nil {
+		return nil, err
+	}
+	rcv.logsConsumer = consumer
+
+	return rcv, nil
+}
@@ -0,0 +1,204 @@
+package flightrecorderreceiver
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"golang.org/x/exp/trace"
+
+	"github.com/florianl/flightrecorderreceiver/internal/metadata"
+)
+
+// Names of the ranges the runtime uses for garbage collection.
+const (
+	rangeGCMarkPhase  = "GC concurrent mark phase"
+	rangeGCMarkAssist = "GC mark assist"
+	// Stop-the-world ranges are named "stop-the-world (<reason>)".
+	rangeSTWPrefix = "stop-the-world ("
+)
+
+// metricGCSTWDuration is the name of the histogram of stop-the-world pauses.
+// Histograms are not supported by the metrics builder, so it is not part of
+// metadata.yaml.
+const metricGCSTWDuration = "go.gc.stw.duration"
+
+// stwPauseBounds are the bucket boundaries in seconds of the histogram of
+// stop-the-world pauses.
+var stwPauseBounds = []float64{0.00001, 0.000025, 0.00005, 0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1}
+
+// stwReason returns the reason of a stop-the-world range. It returns false if
+// name is not the name of a stop-the-world range.
+func stwReason(name string) (string, bool) {
+	reason, ok := strings.CutPrefix(name, rangeSTWPrefix)
+	if !ok {
+		return "", false
+	}
+	return strings.TrimSuffix(reason, ")"), true
+}
+
+// gcRangeKey identifies an active range. Ranges are scoped to a resource, e.g.
+// the goroutine of a mark assist, so the same range can be active on many
+// resources at once.
+type gcRangeKey struct {
+	name  string
+	scope trace.ResourceID
+}
+
+// openRanges tracks the ranges that are active at the current event, so that
+// samples can be attributed to them.
+type openRanges struct {
+	// keys holds the active ranges in the order they began.
+	keys []gcRangeKey
+}
+
+// begin handles EventRangeBegin.
+func (o *openRanges) begin(r trace.Range) {
+	o.keys = append(o.keys, gcRangeKey{name: r.Name, scope: r.Scope})
+}
+
+// end handles EventRangeEnd. It returns false if the range is not active,
+// e.g. because it began before the start of the trace.
+func (o *openRanges) end(r trace.Range) bool {
+	key := gcRangeKey{name: r.Name, scope: r.Scope}
+	for i := len(o.keys) - 1; i >= 0; i-- {
+		if o.keys[i] == key {
+			o.keys = slices.Delete(o.keys, i, i+1)
+			return true
+		}
+	}
+	return false
+}
+
+// innermost returns the name of the most recent active range that applies to
+// the goroutine, or "" if there is none. Ranges apply to the goroutine they
+// are scoped to, e.g. a mark assist, and to all goroutines if they are
+// globally scoped, e.g. the GC mark phase. Ranges scoped to a P, e.g. sweeping,
+// do not apply to the goroutines running on it.
+func (o *openRanges) innermost(goID trace.GoID) string {
+	for i := len(o.keys) - 1; i >= 0; i-- {
+		switch scope := o.keys[i].scope; scope.Kind {
+		case trace.ResourceNone:
+			return o.keys[i].name
+		case trace.ResourceGoroutine:
+			if scope.Goroutine() == goID {
+				return o.keys[i].name
+			}
+		}
+	}
+	return ""
+}
+
+// gcStats collects statistics of garbage collection from the ranges the
+// runtime emits. Ranges that began before the start of the trace are ignored,
+// as their duration is unknown.
+type gcStats struct {
+	// history holds the cycles that began and the ranges that ended in an
+	// earlier flight record, which were counted for that flight record.
+	history sourceHistory
+	// begins maps active ranges to their start.
+	begins map[gcRangeKey]time.Time
+
+	cycles     int64
+	markAssist time.Duration
+	// stwPauses maps the reason of stop-the-world pauses to their durations.
+	stwPauses map[string][]time.Duration
+}
+
+func newGCStats(history sourceHistory) *gcStats {
+	return &gcStats{
+		history:   history,
+		begins:    make(map[gcRangeKey]time.Time),
+		stwPauses: make(map[string][]time.Duration),
+	}
+}
+
+// isGCRange reports whether the range with the given name is tracked.
+func isGCRange(name string) bool {
+	if _, ok := stwReason(name); ok {
+		return true
+	}
+	return name == rangeGCMarkPhase || name == rangeGCMarkAssist
+}
+
+// handleEvent handles EventRangeBegin and EventRangeEnd at ts.
+func (g *gcStats) handleEvent(ev trace.Event, ts time.Time) {
+	r := ev.Range()
+	if !isGCRange(r.Name) {
+		return
+	}
+	key := gcRangeKey{name: r.Name, scope: r.Scope}
+	switch ev.Kind() {
+	case trace.EventRangeBegin:
+		g.begins[key] = ts
+		if r.Name == rangeGCMarkPhase && !g.history.emitted(ts) {
+			g.cycles++
+		}
+	case trace.EventRangeEnd:
+		begin, ok := g.begins[key]
+		if !ok {
+			return
+		}
+		delete(g.begins, key)
+		if g.history.emitted(ts) {
+			return
+		}
+		duration := ts.Sub(begin)
+		if r.Name == rangeGCMarkAssist {
+			g.markAssist += duration
+		} else if reason, ok := stwReason(r.Name); ok {
+			g.stwPauses[reason] = append(g.stwPauses[reason], duration)
+		}
+	}
+}
+
+// recordMetrics records the statistics covering the trace until end in mb.
+func (g *gcStats) recordMetrics(mb *metadata.MetricsBuilder, end time.Time) {
+	endTS := pcommon.NewTimestampFromTime(end)
+
+	mb.RecordGoGcCyclesDataPoint(endTS, g.cycles)
+	mb.RecordGoGcMarkAssistDurationDataPoint(endTS, g.markAssist.Seconds())
+}
+
+// appendSTWPauses appends the histogram of stop-the-world pauses covering
+// [start, end] to metrics. Nothing is appended if there were no pauses.
+func (g *gcStats) appendSTWPauses(metrics pmetric.MetricSlice, start, end time.Time) {
+	if len(g.stwPauses) == 0 {
+		return
+	}
+	startTS := pcommon.NewTimestampFromTime(start)
+	endTS := pcommon.NewTimestampFromTime(end)
+
+	pauses := metrics.AppendEmpty()
+	pauses.SetName(metricGCSTWDuration)
+	pauses.SetDescription("Duration of stop-the-world pauses.")
+	pauses.SetUnit("s")
+	hist := pauses.SetEmptyHistogram()
+	hist.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	for _, reason := range slices.Sorted(maps.Keys(g.stwPauses)) {
+		hdp := hist.DataPoints().AppendEmpty()
+		hdp.SetStartTimestamp(startTS)
+		hdp.SetTimestamp(endTS)
+		hdp.Attributes().PutStr(attrSTWReason, reason)
+		hdp.ExplicitBounds().FromRaw(stwPauseBounds)
+		counts := make([]uint64, len(stwPauseBounds)+1)
+		for _, d := range g.stwPauses[reason] {
+			s := d.Seconds()
+			// Buckets include their upper bound.
+			i, _ := slices.BinarySearch(stwPauseBounds, s)
+			counts[i]++
+			hdp.SetSum(hdp.Sum() + s)
+			if hdp.Count() == 0 || s < hdp.Min() {
+				hdp.SetMin(s)
+			}
+			if hdp.Count() == 0 || s > hdp.Max() {
+				hdp.SetMax(s)
+			}
+			hdp.SetCount(hdp.Count() + 1)
+		}
+		hdp.BucketCounts().FromRaw(counts)
+	}
+}
@@ -0,0 +1,163 @@
+package flightrecorderreceiver
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"golang.org/x/exp/trace"
+
+	"github.com/florianl/flightrecorderreceiver/internal/metadata"
+)
+
+// Reasons for goroutines to wait that count as blocking on synchronization.
+var syncBlockReasons = map[string]bool{
+	"sync":              true,
+	"sync.(*Cond).Wait": true,
+	"chan send":         true,
+	"chan receive":      true,
+	"select":            true,
+}
+
+// networkWaitReason is the reason of goroutines waiting on the network.
+const networkWaitReason = "network"
+
+// goroutineTimes holds the time a single goroutine spent in the categories of
+// the goroutine summary of go tool trace.
+type goroutineTimes struct {
+	exec      time.Duration
+	schedWait time.Duration
+	syncBlock time.Duration
+	syscall   time.Duration
+	network   time.Duration
+	gcAssist  time.Duration
+}
+
+func (t *goroutineTimes) add(o goroutineTimes) {
+	t.exec += o.exec
+	t.schedWait += o.schedWait
+	t.syncBlock += o.syncBlock
+	t.syscall += o.syscall
+	t.network += o.network
+	t.gcAssist += o.gcAssist
+}
+
+// goroutineState is the current state of a goroutine and the time it entered
+// the state.
+type goroutineState struct {
+	state  trace.GoState
+	reason string
+	since  time.Time
+	// assistSince is the start of the active mark assist of the goroutine.
+	// It is zero if the goroutine does not assist the GC.
+	assistSince time.Time
+
+	times goroutineTimes
+}
+
+// account adds the time between the start of the current state and ts to the
+// category of the state. Time that was accounted for an earlier flight record
+// of the source according to history is left out.
+func (s *goroutineState) account(ts time.Time, history sourceHistory) {
+	d := history.unemitted(s.since, ts)
+	switch s.state {
+	case trace.GoRunning:
+		s.times.exec += d
+	case trace.GoRunnable:
+		s.times.schedWait += d
+	case trace.GoSyscall:
+		s.times.syscall += d
+	case trace.GoWaiting:
+		switch {
+		case s.reason == networkWaitReason:
+			s.times.network += d
+		case syncBlockReasons[s.reason]:
+			s.times.syncBlock += d
+		}
+	}
+	s.since = ts
+}
+
+// goroutineSummary breaks down the time of goroutines by their state.
+type goroutineSummary struct {
+	history    sourceHistory
+	goroutines map[trace.GoID]*goroutineState
+}
+
+func newGoroutineSummary(history sourceHistory) *goroutineSummary {
+	return &goroutineSummary{
+		history:    history,
+		goroutines: make(map[trace.GoID]*goroutineState),
+	}
+}
+
+// handleTransition handles a state transition of a goroutine at ts.
+func (s *goroutineSummary) handleTransition(st trace.StateTransition, ts time.Time) {
+	goID := st.Resource.Goroutine()
+	_, to := st.Goroutine()
+	g, ok := s.goroutines[goID]
+	if !ok {
+		// The time a goroutine spent in its state before the first
+		// transition is unknown.
+		g = &goroutineState{}
+		s.goroutines[goID] = g
+	} else {
+		g.account(ts, s.history)
+	}
+	g.state = to
+	g.reason = st.Reason
+	g.since = ts
+}
+
+// handleRange handles EventRangeBegin and EventRangeEnd of mark assists at ts.
+func (s *goroutineSummary) handleRange(ev trace.Event, ts time.Time) {
+	r := ev.Range()
+	if r.Name != rangeGCMarkAssist || r.Scope.Kind != trace.ResourceGoroutine {
+		return
+	}
+	g, ok := s.goroutines[r.Scope.Goroutine()]
+	if !ok {
+		return
+	}
+	switch ev.Kind() {
+	case trace.EventRangeBegin:
+		g.assistSince = ts
+	case trace.EventRangeEnd:
+		if !g.assistSince.IsZero() {
+			g.times.gcAssist += s.history.unemitted(g.assistSince, ts)
+			g.assistSince = time.Time{}
+		}
+	}
+}
+
+// recordMetrics accounts the time of all goroutines until end and records the
+// times per start function in mb.
+func (s *goroutineSummary) recordMetrics(mb *metadata.MetricsBuilder, startFunctions map[trace.GoID]string, end time.Time) {
+	byStartFunction := make(map[string]*goroutineTimes)
+	for goID, g := range s.goroutines {
+		g.account(end, s.history)
+		if !g.assistSince.IsZero() {
+			g.times.gcAssist += s.history.unemitted(g.assistSince, end)
+			g.assistSince = time.Time{}
+		}
+		startFn := startFunctions[goID]
+		times, ok := byStartFunction[startFn]
+		if !ok {
+			times = &goroutineTimes{}
+			byStartFunction[startFn] = times
+		}
+		times.add(g.times)
+	}
+
+	endTS := pcommon.NewTimestampFromTime(end)
+	for _, startFn := range slices.Sorted(maps.Keys(byStartFunction)) {
+		t := byStartFunction[startFn]
+		mb.RecordGoGoroutineExecutionDurationDataPoint(endTS, t.exec.Seconds(), startFn)
+		mb.RecordGoGoroutineSchedWaitDurationDataPoint(endTS, t.schedWait.Seconds(), startFn)
+		mb.RecordGoGoroutineSyncBlockDurationDataPoint(endTS, t.syncBlock.Seconds(), startFn)
+		mb.RecordGoGoroutineSyscallDurationDataPoint(endTS, t.syscall.Seconds(), startFn)
+		mb.RecordGoGoroutineNetworkWaitDurationDataPoint(endTS, t.network.Seconds(), startFn)
+		mb.RecordGoGoroutineGcAssistDurationDataPoint(endTS, t.gcAssist.Seconds(), startFn)
+	}
+}
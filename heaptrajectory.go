@@ -0,0 +1,104 @@
+package flightrecorderreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"golang.org/x/exp/trace"
+
+	"github.com/florianl/flightrecorderreceiver/internal/metadata"
+)
+
+// Names of the runtime/metrics metrics holding the heap size and its goal.
+const (
+	heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+	heapGoalMetric    = "/gc/heap/goal:bytes"
+)
+
+// heapSample is the heap size and goal at a point in time.
+type heapSample struct {
+	ts    time.Time
+	size  int64
+	goal  int64
+	cycle int64
+	phase metadata.AttributeGoGcPhase
+}
+
+// heapTrajectory follows the heap size and its goal across GC cycles. The
+// runtime reports both metrics independently, so they are sampled together
+// whenever one of them changes and whenever the GC starts or stops marking.
+type heapTrajectory struct {
+	size, goal       int64
+	hasSize, hasGoal bool
+	// cycle is the number of GC cycles that started within the trace.
+	cycle   int64
+	marking bool
+
+	samples []heapSample
+}
+
+func newHeapTrajectory() *heapTrajectory {
+	return &heapTrajectory{}
+}
+
+// handleMetric handles EventMetric at ts.
+func (h *heapTrajectory) handleMetric(m trace.Metric, ts time.Time) {
+	switch m.Name {
+	case heapObjectsMetric:
+		h.size, h.hasSize = int64(m.Value.Uint64()), true
+	case heapGoalMetric:
+		h.goal, h.hasGoal = int64(m.Value.Uint64()), true
+	default:
+		return
+	}
+	h.sample(ts)
+}
+
+// handleRange handles EventRangeBegin and EventRangeEnd of the GC mark phase
+// at ts.
+func (h *heapTrajectory) handleRange(ev trace.Event, ts time.Time) {
+	if ev.Range().Name != rangeGCMarkPhase {
+		return
+	}
+	switch ev.Kind() {
+	case trace.EventRangeBegin:
+		h.cycle++
+		h.marking = true
+	case trace.EventRangeEnd:
+		h.marking = false
+	}
+	h.sample(ts)
+}
+
+// sample records the heap size and goal at ts once both are known. Samples
+// at the same time are merged, as the runtime reports both metrics at once.
+func (h *heapTrajectory) sample(ts time.Time) {
+	if !h.hasSize || !h.hasGoal {
+		return
+	}
+	s := heapSample{
+		ts:    ts,
+		size:  h.size,
+		goal:  h.goal,
+		cycle: h.cycle,
+		phase: metadata.AttributeGoGcPhaseOff,
+	}
+	if h.marking {
+		s.phase = metadata.AttributeGoGcPhaseMark
+	}
+	if n := len(h.samples); n > 0 && h.samples[n-1].ts.Equal(ts) {
+		h.samples[n-1] = s
+		return
+	}
+	h.samples = append(h.samples, s)
+}
+
+// recordMetrics records the samples in mb.
+func (h *heapTrajectory) recordMetrics(mb *metadata.MetricsBuilder) {
+	for _, s := range h.samples {
+		ts := pcommon.NewTimestampFromTime(s.ts)
+		mb.RecordGoHeapSizeDataPoint(ts, s.size, s.cycle, s.phase)
+		mb.RecordGoHeapGoalDataPoint(ts, s.goal, s.cycle, s.phase)
+		mb.RecordGoHeapHeadroomDataPoint(ts, s.goal-s.size, s.cycle, s.phase)
+	}
+}
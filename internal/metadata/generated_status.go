@@ -12,6 +12,8 @@ var (
 )
 
 const (
+	TracesStability   = component.StabilityLevelDevelopment
+	LogsStability     = component.StabilityLevelDevelopment
 	ProfilesStability = component.StabilityLevelAlpha
 	MetricsStability  = component.StabilityLevelBeta
 )
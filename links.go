@@ -0,0 +1,159 @@
+package flightrecorderreceiver
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"golang.org/x/exp/trace"
+)
+
+// Supported values for LinksConfig.Extractor.
+const (
+	linkExtractorKeyValue = "key_value"
+	linkExtractorRegex    = "regex"
+)
+
+// Names of the groups of a link pattern that hold the IDs.
+const (
+	linkGroupTraceID = "trace_id"
+	linkGroupSpanID  = "span_id"
+)
+
+// keyValueLinkPattern finds W3C trace and span IDs following the key=value
+// convention, e.g. "trace_id=4bf92f3577b34da6a3ce929d0e0e4736".
+var keyValueLinkPattern = regexp.MustCompile(`\b(?:trace_id=(?P<trace_id>[0-9a-f]{32})|span_id=(?P<span_id>[0-9a-f]{16}))\b`)
+
+// linkPattern returns the pattern of the configured extractor. It returns nil
+// if links are disabled.
+func linkPattern(cfg LinksConfig) (*regexp.Regexp, error) {
+	switch cfg.Extractor {
+	case "":
+		return nil, nil
+	case linkExtractorKeyValue:
+		return keyValueLinkPattern, nil
+	case linkExtractorRegex:
+		pattern, err := regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid links pattern: %w", err)
+		}
+		if pattern.SubexpIndex(linkGroupTraceID) < 0 {
+			return nil, fmt.Errorf("links pattern has no group named %q", linkGroupTraceID)
+		}
+		return pattern, nil
+	default:
+		return nil, fmt.Errorf("unsupported links extractor %q", cfg.Extractor)
+	}
+}
+
+// taskLinks determines the distributed trace each runtime/trace task belongs
+// to, so that samples taken during a task can be linked to it. The IDs are
+// kept with the tasks of annotations.
+//
+// Trace and span IDs are extracted from task names and from log messages of
+// the task. Tasks without IDs of their own inherit those of their parent.
+type taskLinks struct {
+	lt          lookupTable
+	pattern     *regexp.Regexp
+	annotations *userAnnotations
+}
+
+func newTaskLinks(lt lookupTable, cfg LinksConfig, annotations *userAnnotations) (*taskLinks, error) {
+	pattern, err := linkPattern(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &taskLinks{
+		lt:          lt,
+		pattern:     pattern,
+		annotations: annotations,
+	}, nil
+}
+
+// beginTask sets the IDs of a task that began.
+func (l *taskLinks) beginTask(task *annotatedTask) {
+	if l.pattern == nil {
+		return
+	}
+	var base link
+	if task.parent != nil {
+		base = task.parent.link
+	}
+	l.update(task, base, task.typ)
+}
+
+// handleLog updates the IDs of the task of EventLog.
+func (l *taskLinks) handleLog(ev trace.Event) {
+	log := ev.Log()
+	if l.pattern == nil || log.Task == trace.BackgroundTask {
+		return
+	}
+	// Match the category as key, so that trace.Log(ctx, "trace_id", id)
+	// follows the key=value convention.
+	text := log.Message
+	if log.Category != "" {
+		text = log.Category + "=" + log.Message
+	}
+	task := l.annotations.lookupTask(log.Task)
+	l.update(task, task.link, text)
+}
+
+// update sets the IDs of the task to those of base, overridden by the IDs
+// found in text.
+func (l *taskLinks) update(task *annotatedTask, base link, text string) {
+	traceGroup := l.pattern.SubexpIndex(linkGroupTraceID)
+	spanGroup := l.pattern.SubexpIndex(linkGroupSpanID)
+	for _, match := range l.pattern.FindAllStringSubmatch(text, -1) {
+		if traceGroup >= 0 {
+			if traceID, ok := parseTraceID(match[traceGroup]); ok {
+				base.traceID = traceID
+			}
+		}
+		if spanGroup >= 0 {
+			if spanID, ok := parseSpanID(match[spanGroup]); ok {
+				base.spanID = spanID
+			}
+		}
+	}
+	if base.traceID.IsEmpty() {
+		return
+	}
+	task.link = base
+}
+
+// index returns the index of the link of the task. It returns 0 if the task is
+// not linked to a distributed trace.
+func (l *taskLinks) index(id trace.TaskID) int32 {
+	task, ok := l.annotations.tasks[id]
+	if !ok || task.link.traceID.IsEmpty() {
+		return 0
+	}
+	return l.lt.AddLink(task.link.traceID, task.link.spanID)
+}
+
+// parseTraceID parses a W3C trace ID. All zero IDs are invalid.
+func parseTraceID(s string) (pcommon.TraceID, bool) {
+	var id pcommon.TraceID
+	if !decodeID(id[:], s) || id.IsEmpty() {
+		return id, false
+	}
+	return id, true
+}
+
+// parseSpanID parses a W3C span ID. All zero IDs are invalid.
+func parseSpanID(s string) (pcommon.SpanID, bool) {
+	var id pcommon.SpanID
+	if !decodeID(id[:], s) || id.IsEmpty() {
+		return id, false
+	}
+	return id, true
+}
+
+func decodeID(dst []byte, s string) bool {
+	if len(s) != hex.EncodedLen(len(dst)) {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
@@ -0,0 +1,31 @@
+package flightrecorderreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"golang.org/x/exp/trace"
+)
+
+// appendLogRecord converts a runtime/trace.Log call into a log record. If
+// span is not empty, the record is correlated with it.
+func appendLogRecord(records plog.LogRecordSlice, ev trace.Event, ts time.Time, span ptrace.Span) {
+	l := ev.Log()
+
+	record := records.AppendEmpty()
+	record.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	record.Body().SetStr(l.Message)
+	record.Attributes().PutStr(attrLogCategory, l.Category)
+	if goID := ev.Goroutine(); goID != trace.NoGoroutine {
+		record.Attributes().PutInt(attrGoID, int64(goID))
+	}
+	if l.Task != trace.BackgroundTask {
+		record.Attributes().PutInt(attrTaskID, int64(l.Task))
+	}
+	if span != (ptrace.Span{}) {
+		record.SetTraceID(span.TraceID())
+		record.SetSpanID(span.SpanID())
+	}
+}
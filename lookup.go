@@ -4,6 +4,7 @@ import (
 	"unsafe"
 
 	"github.com/zeebo/xxh3"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"golang.org/x/exp/trace"
 )
 
@@ -23,6 +24,15 @@ type kvu struct {
 	// TODO: convert value to something like AnyValue
 	// https://github.com/open-telemetry/opentelemetry-proto/blob/d6dc40fe54b8d441fd7a920af29d96c3ba6ed36a/opentelemetry/proto/profiles/v1development/profiles.proto#L495
 	value string
+	// intValue is the value of integer attributes, which have isInt set.
+	intValue int64
+	isInt    bool
+}
+
+// link is a helper struct for the links table.
+type link struct {
+	traceID pcommon.TraceID
+	spanID  pcommon.SpanID
 }
 
 // stackInfo is a helper struct for the stacks table.
@@ -48,9 +58,9 @@ type locationInfo struct {
 
 type lookupTable struct {
 	// mappings - currently not used
-	locations map[uint64]locationInfo
-	functions map[fn]int32
-	// links - currently not used
+	locations  map[uint64]locationInfo
+	functions  map[fn]int32
+	links      map[link]int32
 	strings    map[string]int32
 	attributes map[kvu]int32
 	stacks     map[uint64]stackInfo
@@ -65,6 +75,9 @@ func createLookupTable() lookupTable {
 	functions := make(map[fn]int32)
 	functions[fn{}] = 0
 
+	links := make(map[link]int32)
+	links[link{}] = 0
+
 	strings := make(map[string]int32)
 	strings[""] = 0
 
@@ -77,6 +90,7 @@ func createLookupTable() lookupTable {
 	return lookupTable{
 		locations:  locations,
 		functions:  functions,
+		links:      links,
 		strings:    strings,
 		attributes: attributes,
 		stacks:     stacks,
@@ -109,6 +123,24 @@ func (lt *lookupTable) AddKeyValueUnit(k, v, u string) int32 {
 	return idx
 }
 
+// AddKeyIntValueUnit returns an index to the given integer attribute in the
+// lookup table.
+func (lt *lookupTable) AddKeyIntValueUnit(k string, v int64, u string) int32 {
+	attr := kvu{
+		keyIdx:   lt.AddString(k),
+		intValue: v,
+		isInt:    true,
+		unitIdx:  lt.AddString(u),
+	}
+	if idx, exists := lt.attributes[attr]; exists {
+		return idx
+	}
+
+	idx := int32(len(lt.attributes))
+	lt.attributes[attr] = idx
+	return idx
+}
+
 // AddFunction returns an index to the given function in the lookup table.
 func (lt *lookupTable) AddFunction(name, systemName, fileName string, startLine int64) int32 {
 	attr := fn{
@@ -126,6 +158,21 @@ func (lt *lookupTable) AddFunction(name, systemName, fileName string, startLine
 	return idx
 }
 
+// AddLink returns an index to the given link in the lookup table.
+func (lt *lookupTable) AddLink(traceID pcommon.TraceID, spanID pcommon.SpanID) int32 {
+	l := link{
+		traceID: traceID,
+		spanID:  spanID,
+	}
+	if idx, exists := lt.links[l]; exists {
+		return idx
+	}
+
+	idx := int32(len(lt.links))
+	lt.links[l] = idx
+	return idx
+}
+
 func hashLocations(locs []int32) uint64 {
 	// Reinterpret the []int32 as a []byte slice.
 	// Since an int32 is 4 bytes, the new length is len(locs) * 4.
@@ -135,6 +182,12 @@ func hashLocations(locs []int32) uint64 {
 	return xxh3.Hash(b)
 }
 
+// sampleKey returns the identity of a sample with the given stack, link and
+// attributes. Samples with the same identity can be merged.
+func sampleKey(stackIdx, linkIdx int32, attributeIndices []int32) uint64 {
+	return hashLocations(append([]int32{stackIdx, linkIdx}, attributeIndices...))
+}
+
 func (lt *lookupTable) AddStack(locs []int32) int32 {
 	if len(locs) == 0 {
 		return 0
@@ -0,0 +1,347 @@
+package flightrecorderreceiver
+
+import (
+	"crypto/rand"
+	"slices"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"golang.org/x/exp/trace"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+)
+
+// Supported values for Config.GroupBy.
+const (
+	groupByGoroutine = "goroutine"
+	groupByRangeName = "range_name"
+	groupByFile      = "file"
+	groupByWindow    = "window"
+)
+
+// rangeState tracks the samples of a single goroutine within the range they
+// are attributed to.
+type rangeState struct {
+	// name is the name of the range. It is empty for samples outside of a
+	// range.
+	name        string
+	startTS     time.Time
+	lastEventTS time.Time
+
+	// profiles holds all profiles that received samples of this range.
+	profiles []*profileState
+
+	// pending is the most recent sample of the range. Its wall time value is
+	// only known once the next sample is taken or the range ends.
+	pending    pprofile.Sample
+	pendingIdx int
+	hasPending bool
+}
+
+// addSample records a sample of the range at ts in p. The value of the sample
+// is set once the next sample is taken or the range ends.
+func (s *rangeState) addSample(p *profileState, stackIdx, linkIdx int32, attributeIndices []int32, ts time.Time, aggregate bool) {
+	s.finishSample(ts)
+	s.lastEventTS = ts
+	if len(s.profiles) == 0 || s.profiles[len(s.profiles)-1] != p {
+		s.profiles = append(s.profiles, p)
+	}
+	s.pending, s.pendingIdx = p.addSample(stackIdx, linkIdx, attributeIndices, ts, 0, aggregate)
+	s.hasPending = true
+}
+
+// finishSample sets the value of the pending sample to the wall time that
+// passed between taking it and ts.
+func (s *rangeState) finishSample(ts time.Time) {
+	if !s.hasPending {
+		return
+	}
+	s.pending.Values().SetAt(s.pendingIdx, ts.Sub(s.lastEventTS).Nanoseconds())
+	s.hasPending = false
+}
+
+// finishPendingSamples finishes the pending samples of the goroutines ev
+// belongs to at ts. The value of a sample is the time until the next event of
+// its goroutine, regardless of whether that event has a stack or is sampled.
+func finishPendingSamples(activeRanges map[trace.GoID]*rangeState, ev trace.Event, ts time.Time) {
+	if state, ok := activeRanges[ev.Goroutine()]; ok {
+		state.finishSample(ts)
+	}
+	if ev.Kind() != trace.EventStateTransition {
+		return
+	}
+	// Transitions like GoUnblock are executed by another goroutine.
+	if st := ev.StateTransition(); st.Resource.Kind == trace.ResourceGoroutine {
+		if state, ok := activeRanges[st.Resource.Goroutine()]; ok {
+			state.finishSample(ts)
+		}
+	}
+}
+
+// end finishes the pending sample and extends the profiles of the range to
+// cover it until endTS.
+func (s *rangeState) end(endTS time.Time) {
+	s.finishSample(endTS)
+	for _, p := range s.profiles {
+		p.cover(s.startTS, endTS)
+	}
+}
+
+// profileState tracks a single profile and the time range it covers.
+type profileState struct {
+	profile    pprofile.Profile
+	sampleType string
+	startTS    time.Time
+	endTS      time.Time
+	// fixed is set if the time range of the profile does not depend on
+	// its samples.
+	fixed bool
+
+	// samples maps the identity of a sample to the sample if samples are
+	// aggregated.
+	samples map[uint64]pprofile.Sample
+}
+
+// cover extends the time range of the profile to include [start, end].
+func (p *profileState) cover(start, end time.Time) {
+	if p.fixed {
+		return
+	}
+	if p.startTS.IsZero() || start.Before(p.startTS) {
+		p.startTS = start
+	}
+	if end.After(p.endTS) {
+		p.endTS = end
+	}
+}
+
+// addSample records value for the given stack, link and attributes at ts. If
+// aggregate is set, observations with identical stack, link and attributes are
+// merged into a single sample with many timestamps. It returns the sample and
+// the index of the recorded value.
+func (p *profileState) addSample(stackIdx, linkIdx int32, attributeIndices []int32, ts time.Time, value int64, aggregate bool) (pprofile.Sample, int) {
+	key := sampleKey(stackIdx, linkIdx, attributeIndices)
+	sample, exists := p.samples[key]
+	if !aggregate || !exists {
+		sample = p.profile.Samples().AppendEmpty()
+		sample.SetStackIndex(stackIdx)
+		sample.SetLinkIndex(linkIdx)
+		sample.AttributeIndices().FromRaw(attributeIndices)
+		if aggregate {
+			if p.samples == nil {
+				p.samples = make(map[uint64]pprofile.Sample)
+			}
+			p.samples[key] = sample
+		}
+	}
+	// Timestamps and values are parallel arrays, where the elements at the
+	// same index refer to the same observation.
+	sample.TimestampsUnixNano().Append(uint64(ts.UnixNano()))
+	sample.Values().Append(value)
+	return sample, sample.Values().Len() - 1
+}
+
+// finalize sets time and duration of the profile.
+func (p *profileState) finalize() {
+	p.profile.SetTime(pcommon.NewTimestampFromTime(p.startTS))
+	// Use +1 so the half-open range [startTS, endTS+1) includes any sample
+	// that lands at exactly endTS (state transitions can share a timestamp
+	// with the range-end event).
+	p.profile.SetDurationNano(uint64(p.endTS.Sub(p.startTS).Nanoseconds()) + 1)
+}
+
+// profileKey identifies the profile a sample is added to.
+type profileKey struct {
+	sampleType string
+	// rng is set if profiles are grouped by goroutine.
+	rng *rangeState
+	// rangeName is set if profiles are grouped by range name.
+	rangeName string
+	// window is the start of the window in Unix nanoseconds if profiles are
+	// grouped by window.
+	window int64
+}
+
+// profileGroups creates profiles lazily on their first sample, according
+// to the configured grouping.
+type profileGroups struct {
+	lt      lookupTable
+	spSlice pprofile.ScopeProfilesSlice
+	groupBy string
+	window  time.Duration
+
+	profiles map[profileKey]*profileState
+	// order holds the profiles in the order of their creation.
+	order []*profileState
+}
+
+func newProfileGroups(lt lookupTable, spSlice pprofile.ScopeProfilesSlice, cfg *Config) *profileGroups {
+	return &profileGroups{
+		lt:       lt,
+		spSlice:  spSlice,
+		groupBy:  cfg.GroupBy,
+		window:   cfg.Window,
+		profiles: make(map[profileKey]*profileState),
+	}
+}
+
+// get returns the profile of the given sample type for a sample at ts. state
+// is the range the sample belongs to and may be nil for samples that are not
+// part of a range. These are grouped per file unless profiles are grouped by
+// window.
+func (g *profileGroups) get(sampleType, unit string, state *rangeState, ts time.Time) *profileState {
+	key := profileKey{sampleType: sampleType}
+	switch g.groupBy {
+	case groupByGoroutine:
+		key.rng = state
+	case groupByRangeName:
+		if state != nil {
+			key.rangeName = state.name
+		}
+	case groupByWindow:
+		key.window = ts.UnixNano() - ts.UnixNano()%g.window.Nanoseconds()
+	}
+
+	if p, exists := g.profiles[key]; exists {
+		return p
+	}
+
+	sp := g.spSlice.AppendEmpty()
+	sp.SetSchemaUrl(semconv.SchemaURL)
+	p := &profileState{
+		profile:    sp.Profiles().AppendEmpty(),
+		sampleType: sampleType,
+	}
+	p.profile.SetProfileID(newProfileID())
+	initializeProfile(g.lt, p.profile, sampleType, unit)
+	if g.groupBy == groupByWindow {
+		p.startTS = time.Unix(0, key.window)
+		p.endTS = p.startTS.Add(g.window - 1)
+		p.fixed = true
+	}
+	g.profiles[key] = p
+	g.order = append(g.order, p)
+	return p
+}
+
+// finalize sets time and duration of all profiles.
+func (g *profileGroups) finalize() {
+	for _, p := range g.profiles {
+		p.finalize()
+	}
+}
+
+// profileTimeline holds the profiles of a sample type sorted by their start.
+type profileTimeline struct {
+	sampleType string
+	profiles   []*profileState
+	// maxEnd holds the latest end of profiles[:i+1] at index i.
+	maxEnd []time.Time
+}
+
+// timelines returns the timeline of each sample type in the order the sample
+// types were first recorded.
+func (g *profileGroups) timelines() []*profileTimeline {
+	var timelines []*profileTimeline
+	bySampleType := make(map[string]*profileTimeline)
+	for _, p := range g.order {
+		tl, ok := bySampleType[p.sampleType]
+		if !ok {
+			tl = &profileTimeline{sampleType: p.sampleType}
+			bySampleType[p.sampleType] = tl
+			timelines = append(timelines, tl)
+		}
+		tl.profiles = append(tl.profiles, p)
+	}
+	for _, tl := range timelines {
+		slices.SortStableFunc(tl.profiles, func(a, b *profileState) int {
+			return a.startTS.Compare(b.startTS)
+		})
+		tl.maxEnd = make([]time.Time, len(tl.profiles))
+		for i, p := range tl.profiles {
+			tl.maxEnd[i] = p.endTS
+			if i > 0 && tl.maxEnd[i-1].After(p.endTS) {
+				tl.maxEnd[i] = tl.maxEnd[i-1]
+			}
+		}
+	}
+	return timelines
+}
+
+// covering returns the profile whose time range covers ts. If several
+// profiles cover ts, the one with the shortest time range is returned, as its
+// stacks are the most specific to ts. It returns nil if no profile covers ts.
+func (tl *profileTimeline) covering(ts time.Time) *profileState {
+	// Profiles that start after ts cannot cover it.
+	n := sort.Search(len(tl.profiles), func(i int) bool {
+		return tl.profiles[i].startTS.After(ts)
+	})
+	var covering *profileState
+	// Profiles cover the half-open range [startTS, endTS+1). The search stops
+	// at the first profile after which no earlier profile ends at or after ts.
+	for i := n - 1; i >= 0 && !tl.maxEnd[i].Before(ts); i-- {
+		p := tl.profiles[i]
+		if p.endTS.Before(ts) {
+			continue
+		}
+		if covering == nil || p.endTS.Sub(p.startTS) < covering.endTS.Sub(covering.startTS) {
+			covering = p
+		}
+	}
+	return covering
+}
+
+// appendExemplars attaches exemplars to the data points of the gauges in
+// metrics that refer to the profiles covering their timestamp, so that a
+// metric value can be traced back to the stacks recorded at the same time.
+// Non-monotonic sums are treated as gauges, as they hold the current value
+// of a runtime/metrics gauge if semconv_metric_names is set.
+func (g *profileGroups) appendExemplars(metrics []pmetric.Metric) {
+	timelines := g.timelines()
+	for _, metric := range metrics {
+		var dps pmetric.NumberDataPointSlice
+		switch metric.Type() {
+		case pmetric.MetricTypeGauge:
+			dps = metric.Gauge().DataPoints()
+		case pmetric.MetricTypeSum:
+			if metric.Sum().IsMonotonic() {
+				continue
+			}
+			dps = metric.Sum().DataPoints()
+		default:
+			continue
+		}
+		for _, dp := range dps.All() {
+			for _, tl := range timelines {
+				p := tl.covering(dp.Timestamp().AsTime())
+				if p == nil {
+					continue
+				}
+				exemplar := dp.Exemplars().AppendEmpty()
+				exemplar.SetTimestamp(dp.Timestamp())
+				if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+					exemplar.SetIntValue(dp.IntValue())
+				} else {
+					exemplar.SetDoubleValue(dp.DoubleValue())
+				}
+				exemplar.FilteredAttributes().PutStr(attrProfileID, p.profile.ProfileID().String())
+				exemplar.FilteredAttributes().PutStr(attrProfileType, p.sampleType)
+			}
+		}
+	}
+}
+
+func newProfileID() pprofile.ProfileID {
+	var id pprofile.ProfileID
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+// initializeProfile sets the sample type of Profile.
+func initializeProfile(lt lookupTable, p pprofile.Profile, sampleType, unit string) {
+	p.SampleType().SetTypeStrindex(lt.AddString(sampleType))
+	p.SampleType().SetUnitStrindex(lt.AddString(unit))
+}
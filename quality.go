@@ -0,0 +1,72 @@
+package flightrecorderreceiver
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"golang.org/x/exp/trace"
+
+	"github.com/florianl/flightrecorderreceiver/internal/metadata"
+)
+
+// droppedEventsKey identifies events of the trace that were not converted.
+type droppedEventsKey struct {
+	reason metadata.AttributeFlightrecorderDropReason
+	kind   string
+}
+
+// traceQuality counts events of a trace and the problems found while
+// converting them, so the quality of traces can be monitored.
+type traceQuality struct {
+	history sourceHistory
+	// counting is set if the current event is counted.
+	counting bool
+
+	events  int64
+	dropped map[droppedEventsKey]int64
+	// withoutRangeBegin holds goroutines with samples outside of a range
+	// that began within the trace.
+	withoutRangeBegin map[trace.GoID]bool
+}
+
+func newTraceQuality(history sourceHistory) *traceQuality {
+	return &traceQuality{
+		history:           history,
+		dropped:           make(map[droppedEventsKey]int64),
+		withoutRangeBegin: make(map[trace.GoID]bool),
+	}
+}
+
+// count counts the current event, which happened at ts, unless it was counted
+// for an earlier flight record of the source. ts is zero for events before the
+// clock synchronization, whose wall time is unknown. These are always counted.
+func (q *traceQuality) count(ts time.Time) {
+	q.counting = ts.IsZero() || !q.history.emitted(ts)
+	if q.counting {
+		q.events++
+	}
+}
+
+// drop counts the current event of the given kind as not converted.
+func (q *traceQuality) drop(reason metadata.AttributeFlightrecorderDropReason, kind trace.EventKind) {
+	if !q.counting {
+		return
+	}
+	q.dropped[droppedEventsKey{reason: reason, kind: kind.String()}]++
+}
+
+// recordMetrics records the counts in mb.
+func (q *traceQuality) recordMetrics(mb *metadata.MetricsBuilder, end time.Time) {
+	endTS := pcommon.NewTimestampFromTime(end)
+	mb.RecordFlightrecorderTraceEventsDataPoint(endTS, q.events)
+	keys := slices.SortedFunc(maps.Keys(q.dropped), func(a, b droppedEventsKey) int {
+		return cmp.Or(cmp.Compare(a.reason, b.reason), cmp.Compare(a.kind, b.kind))
+	})
+	for _, key := range keys {
+		mb.RecordFlightrecorderTraceEventsDroppedDataPoint(endTS, q.dropped[key], key.reason, key.kind)
+	}
+	mb.RecordFlightrecorderTraceGoroutinesWithoutRangeBeginDataPoint(endTS, int64(len(q.withoutRangeBegin)))
+}
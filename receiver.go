@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"os"
+	"regexp"
 	"sync"
 	"time"
 
@@ -11,35 +12,72 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/xconsumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver"
 	"go.uber.org/zap"
+
+	"github.com/florianl/flightrecorderreceiver/internal/metadata"
 )
 
 // flightRecorderReceiver is a custom receiver that parses flight recorder
-// files and emits both profiles and metrics to their respective consumers.
+// files and emits profiles, metrics, traces and logs to their respective consumers.
 type flightRecorderReceiver struct {
-	cfg    *Config
-	logger *zap.Logger
+	cfg      *Config
+	settings receiver.Settings
+	logger   *zap.Logger
 
 	profilesConsumer xconsumer.Profiles // may be nil
 	metricsConsumer  consumer.Metrics   // may be nil
+	tracesConsumer   consumer.Traces    // may be nil
+	logsConsumer     consumer.Logs      // may be nil
+
+	// sourcePattern identifies the source of a flight record by its path. It
+	// is nil if there is none.
+	sourcePattern *regexp.Regexp
+	// dedup is nil if deduplication is disabled. It is shared by all scrapes
+	// of the receiver.
+	dedup *deduplicator
 
+	// mu guards cancel.
+	mu sync.Mutex
+	// cancel stops the scraping loop. It is nil if the loop is not running.
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
 }
 
 // newFlightRecorderReceiver creates a new flight recorder receiver instance.
-func newFlightRecorderReceiver(cfg *Config, settings component.TelemetrySettings) *flightRecorderReceiver {
-	return &flightRecorderReceiver{
-		cfg:    cfg,
-		logger: settings.Logger,
+func newFlightRecorderReceiver(cfg *Config, settings receiver.Settings) (*flightRecorderReceiver, error) {
+	pattern, err := sourcePattern(cfg.Deduplication)
+	if err != nil {
+		return nil, err
 	}
+	return &flightRecorderReceiver{
+		cfg:           cfg,
+		settings:      settings,
+		logger:        settings.Logger,
+		sourcePattern: pattern,
+		dedup:         newDeduplicator(cfg.Deduplication),
+	}, nil
 }
 
-// Start begins the receiver's scraping loop in a background goroutine.
-func (r *flightRecorderReceiver) Start(ctx context.Context, _ component.Host) error {
-	ctx, r.cancel = context.WithCancel(ctx)
+// Start begins the receiver's scraping loop in a background goroutine. The
+// receiver is shared by the pipelines of all signals and each of them starts
+// it, so only the first call starts the loop.
+func (r *flightRecorderReceiver) Start(_ context.Context, _ component.Host) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancel != nil {
+		return nil
+	}
+
+	// The context of Start must not be used for the scraping loop, as it
+	// only covers the start of the receiver.
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
 
 	r.wg.Go(func() {
 		r.run(ctx)
@@ -50,9 +88,12 @@ func (r *flightRecorderReceiver) Start(ctx context.Context, _ component.Host) er
 
 // Shutdown stops the receiver and waits for the scraping goroutine to exit.
 func (r *flightRecorderReceiver) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
 	if r.cancel != nil {
 		r.cancel()
+		r.cancel = nil
 	}
+	r.mu.Unlock()
 
 	done := make(chan struct{})
 	go func() {
@@ -99,7 +140,7 @@ func (r *flightRecorderReceiver) run(ctx context.Context) {
 }
 
 // scrapeAndEmit reads all matching flight recorder files, parses them,
-// and emits the results to the profiles and metrics consumers.
+// and emits the results to the profiles, metrics, traces and logs consumers.
 func (r *flightRecorderReceiver) scrapeAndEmit(ctx context.Context) error {
 	matches, err := doublestar.FilepathGlob(r.cfg.Include)
 	if err != nil {
@@ -109,6 +150,8 @@ func (r *flightRecorderReceiver) scrapeAndEmit(ctx context.Context) error {
 	var scrapeErrors []error
 	profiles := pprofile.NewProfiles()
 	metrics := pmetric.NewMetrics()
+	traces := ptrace.NewTraces()
+	logs := plog.NewLogs()
 
 	for _, match := range matches {
 		f, err := os.Open(match)
@@ -117,23 +160,44 @@ func (r *flightRecorderReceiver) scrapeAndEmit(ctx context.Context) error {
 			continue
 		}
 
-		newProfiles, newMetrics, convertErr := convert(ctx, r.logger, f)
+		source := flightRecordSource(r.sourcePattern, match)
+		converted, convertErr := convert(ctx, r.settings, r.cfg, f, r.dedup.history(source))
 		if convertErr != nil {
 			scrapeErrors = append(scrapeErrors, convertErr)
 			f.Close()
 			continue
 		}
 
+		rb := metadata.NewResourceBuilder(r.cfg.ResourceAttributes)
+		rb.SetFlightrecorderFilePath(match)
+		rb.SetFlightrecorderSource(source)
+		setResource(converted, rb.Emit())
+
+		// Drop data emitted before for an earlier flight record of the
+		// same source.
+		if r.dedup != nil {
+			r.dedup.deduplicate(source, converted)
+		}
+
 		// Merge profiles
-		if err := newProfiles.MergeTo(profiles); err != nil {
+		if err := converted.profiles.MergeTo(profiles); err != nil {
 			scrapeErrors = append(scrapeErrors, err)
 		}
 
 		// Merge metrics
-		mergeMetrics(newMetrics, metrics)
+		mergeMetrics(converted.metrics, metrics)
+
+		// Merge traces
+		mergeTraces(converted.traces, traces)
+
+		// Merge logs
+		mergeLogs(converted.logs, logs)
 
 		f.Close()
 	}
+	if r.dedup != nil {
+		r.dedup.finishScrape()
+	}
 
 	// Emit profiles if consumer is configured
 	if r.profilesConsumer != nil && profiles.ResourceProfiles().Len() > 0 {
@@ -149,6 +213,20 @@ func (r *flightRecorderReceiver) scrapeAndEmit(ctx context.Context) error {
 		}
 	}
 
+	// Emit traces if consumer is configured
+	if r.tracesConsumer != nil && traces.SpanCount() > 0 {
+		if err := r.tracesConsumer.ConsumeTraces(ctx, traces); err != nil {
+			scrapeErrors = append(scrapeErrors, err)
+		}
+	}
+
+	// Emit logs if consumer is configured
+	if r.logsConsumer != nil && logs.LogRecordCount() > 0 {
+		if err := r.logsConsumer.ConsumeLogs(ctx, logs); err != nil {
+			scrapeErrors = append(scrapeErrors, err)
+		}
+	}
+
 	if len(scrapeErrors) > 0 {
 		return errors.Join(scrapeErrors...)
 	}
@@ -156,7 +234,33 @@ func (r *flightRecorderReceiver) scrapeAndEmit(ctx context.Context) error {
 	return nil
 }
 
+// setResource sets res as the resource of all signals of s.
+func setResource(s signals, res pcommon.Resource) {
+	for _, rp := range s.profiles.ResourceProfiles().All() {
+		res.CopyTo(rp.Resource())
+	}
+	for _, rm := range s.metrics.ResourceMetrics().All() {
+		res.CopyTo(rm.Resource())
+	}
+	for _, rs := range s.traces.ResourceSpans().All() {
+		res.CopyTo(rs.Resource())
+	}
+	for _, rl := range s.logs.ResourceLogs().All() {
+		res.CopyTo(rl.Resource())
+	}
+}
+
 // mergeMetrics merges metrics from src into dst.
 func mergeMetrics(src, dst pmetric.Metrics) {
 	src.ResourceMetrics().MoveAndAppendTo(dst.ResourceMetrics())
 }
+
+// mergeTraces merges traces from src into dst.
+func mergeTraces(src, dst ptrace.Traces) {
+	src.ResourceSpans().MoveAndAppendTo(dst.ResourceSpans())
+}
+
+// mergeLogs merges logs from src into dst.
+func mergeLogs(src, dst plog.Logs) {
+	src.ResourceLogs().MoveAndAppendTo(dst.ResourceLogs())
+}
@@ -0,0 +1,109 @@
+package flightrecorderreceiver
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/florianl/flightrecorderreceiver/internal/metadata"
+)
+
+// waitFor fails the test if cond does not become true within a few seconds.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the receiver")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReceiverResourceAttributes(t *testing.T) {
+	f, cleanup := generateFlightrecord(t)
+	defer cleanup()
+	path := f.(*os.File).Name()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = path
+	cfg.InitialDelay = 0
+	cfg.Deduplication.SourcePattern = `flightrecord-(\d+)`
+	cfg.ResourceAttributes.FlightrecorderSource.Enabled = true
+
+	sink := new(consumertest.MetricsSink)
+	rcv, err := NewFactory().CreateMetrics(t.Context(), receivertest.NewNopSettings(metadata.Type), cfg, sink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rcv.Start(t.Context(), componenttest.NewNopHost()); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := rcv.Shutdown(t.Context()); err != nil {
+			t.Error(err)
+		}
+	}()
+	waitFor(t, func() bool { return len(sink.AllMetrics()) > 0 })
+
+	res := sink.AllMetrics()[0].ResourceMetrics().At(0).Resource()
+	if got, ok := res.Attributes().Get("flightrecorder.file.path"); !ok || got.Str() != path {
+		t.Fatalf("expected flightrecorder.file.path %q, got %v", path, res.Attributes().AsRaw())
+	}
+	pattern, err := sourcePattern(cfg.Deduplication)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := flightRecordSource(pattern, path)
+	if want == path {
+		t.Fatalf("expected source pattern to match %q", path)
+	}
+	if got, ok := res.Attributes().Get("flightrecorder.source"); !ok || got.Str() != want {
+		t.Fatalf("expected flightrecorder.source %q, got %v", want, res.Attributes().AsRaw())
+	}
+}
+
+func TestReceiverStartsOnce(t *testing.T) {
+	f, cleanup := generateFlightrecord(t)
+	defer cleanup()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = f.(*os.File).Name()
+	cfg.InitialDelay = 0
+	cfg.CollectionInterval = time.Hour
+	// Deduplication would hide the data of a second scraping loop.
+	cfg.Deduplication.Enabled = false
+
+	factory := NewFactory()
+	settings := receivertest.NewNopSettings(metadata.Type)
+	metricsSink := new(consumertest.MetricsSink)
+	metricsRcv, err := factory.CreateMetrics(t.Context(), settings, cfg, metricsSink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logsRcv, err := factory.CreateLogs(t.Context(), settings, cfg, consumertest.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The collector starts the shared receiver once per pipeline.
+	for _, rcv := range []component.Component{metricsRcv, logsRcv, metricsRcv} {
+		if err := rcv.Start(t.Context(), componenttest.NewNopHost()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(t, func() bool { return len(metricsSink.AllMetrics()) > 0 })
+	for _, rcv := range []component.Component{metricsRcv, logsRcv} {
+		if err := rcv.Shutdown(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := len(metricsSink.AllMetrics()); got != 1 {
+		t.Fatalf("expected a single scrape, got %d", got)
+	}
+}
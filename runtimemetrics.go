@@ -0,0 +1,213 @@
+package flightrecorderreceiver
+
+import (
+	"fmt"
+	"regexp"
+	runtimemetrics "runtime/metrics"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"golang.org/x/exp/trace"
+)
+
+// runtimeMetrics maps the names of runtime/metrics to their description. The
+// descriptions are those of the Go version of the receiver, which can differ
+// from the version of the recorded program.
+var runtimeMetrics = func() map[string]runtimemetrics.Description {
+	descs := make(map[string]runtimemetrics.Description)
+	for _, desc := range runtimemetrics.All() {
+		descs[desc.Name] = desc
+	}
+	return descs
+}()
+
+// initRuntimeMetric sets the type of metric according to the description of
+// the runtime/metrics metric with the given name. Cumulative metrics become
+// monotonic cumulative sums, all others gauges. Metrics renamed according to
+// the semantic conventions get the type the semantic conventions define.
+func initRuntimeMetric(metric pmetric.Metric, traceName string, semconvNames bool) {
+	if _, ok := semconvMetrics[traceName]; ok && semconvNames {
+		initUpDownCounter(metric)
+		return
+	}
+	if desc, ok := runtimeMetrics[traceName]; ok && desc.Cumulative {
+		sum := metric.SetEmptySum()
+		sum.SetIsMonotonic(true)
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		return
+	}
+	metric.SetEmptyGauge()
+}
+
+// appendRuntimeMetricDataPoint appends the value of m at ts to metric. start
+// is the start timestamp of data points of cumulative sums.
+func appendRuntimeMetricDataPoint(metric pmetric.Metric, m trace.Metric, ts, start time.Time) {
+	var dp pmetric.NumberDataPoint
+	if metric.Type() == pmetric.MetricTypeSum {
+		dp = metric.Sum().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	} else {
+		dp = metric.Gauge().DataPoints().AppendEmpty()
+	}
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	// Flight recorder metrics are uint64 values.
+	v := m.Value.Uint64()
+	if desc, ok := runtimeMetrics[m.Name]; ok && desc.Kind == runtimemetrics.KindFloat64 {
+		dp.SetDoubleValue(float64(v))
+		return
+	}
+	dp.SetIntValue(int64(v))
+}
+
+// Supported values for Config.MetricsAggregation.
+const (
+	metricsAggregationLast = "last"
+	metricsAggregationMin  = "min"
+	metricsAggregationMax  = "max"
+	metricsAggregationAvg  = "avg"
+)
+
+// metricsWindow accumulates the data points of a runtime/metrics metric within
+// a window.
+type metricsWindow struct {
+	count    int
+	sum      float64
+	last     pmetric.NumberDataPoint
+	min, max pmetric.NumberDataPoint
+}
+
+func numberValue(dp pmetric.NumberDataPoint) float64 {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return float64(dp.IntValue())
+	}
+	return dp.DoubleValue()
+}
+
+func (w *metricsWindow) add(dp pmetric.NumberDataPoint) {
+	v := numberValue(dp)
+	if w.count == 0 || v < numberValue(w.min) {
+		w.min = dp
+	}
+	if w.count == 0 || v > numberValue(w.max) {
+		w.max = dp
+	}
+	w.count++
+	w.sum += v
+	w.last = dp
+}
+
+// appendTo appends the aggregate of the window to dps. It has the timestamp of
+// the last data point of the window.
+func (w *metricsWindow) appendTo(dps pmetric.NumberDataPointSlice, aggregation string) {
+	dp := dps.AppendEmpty()
+	w.last.CopyTo(dp)
+	switch aggregation {
+	case metricsAggregationMin:
+		setNumberValue(dp, w.min)
+	case metricsAggregationMax:
+		setNumberValue(dp, w.max)
+	case metricsAggregationAvg:
+		dp.SetDoubleValue(w.sum / float64(w.count))
+	}
+}
+
+func setNumberValue(dp, from pmetric.NumberDataPoint) {
+	if from.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		dp.SetIntValue(from.IntValue())
+		return
+	}
+	dp.SetDoubleValue(from.DoubleValue())
+}
+
+// downsampleRuntimeMetric replaces the data points of metric by a single
+// data point per window of the given resolution, or per flight record if
+// summary is set. Sums are cumulative, so only their last data point of a
+// window is kept regardless of aggregation.
+func downsampleRuntimeMetric(metric pmetric.Metric, resolution time.Duration, aggregation string, summary bool) {
+	if resolution <= 0 && !summary {
+		return
+	}
+	var dps pmetric.NumberDataPointSlice
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps = metric.Gauge().DataPoints()
+	case pmetric.MetricTypeSum:
+		dps = metric.Sum().DataPoints()
+		aggregation = metricsAggregationLast
+	default:
+		return
+	}
+
+	downsampled := pmetric.NewNumberDataPointSlice()
+	var window metricsWindow
+	var windowStart int64
+	for _, dp := range dps.All() {
+		var start int64
+		if !summary {
+			// Windows are aligned to multiples of the resolution, so
+			// data points of many flight records line up.
+			ts := int64(dp.Timestamp())
+			start = ts - ts%resolution.Nanoseconds()
+		}
+		if window.count > 0 && start != windowStart {
+			window.appendTo(downsampled, aggregation)
+			window = metricsWindow{}
+		}
+		windowStart = start
+		window.add(dp)
+	}
+	if window.count > 0 {
+		window.appendTo(downsampled, aggregation)
+	}
+	dps.RemoveIf(func(pmetric.NumberDataPoint) bool { return true })
+	downsampled.MoveAndAppendTo(dps)
+}
+
+// runtimeMetricsFilter selects the runtime/metrics metrics of the trace that
+// are emitted.
+type runtimeMetricsFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+func newRuntimeMetricsFilter(cfg RuntimeMetricsConfig) (*runtimeMetricsFilter, error) {
+	include, err := compilePatterns("include", cfg.Include)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := compilePatterns("exclude", cfg.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	return &runtimeMetricsFilter{include: include, exclude: exclude}, nil
+}
+
+func compilePatterns(field string, patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid runtime_metrics %s pattern: %w", field, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// match reports whether the runtime/metrics metric with the given name is
+// emitted. Without include patterns all metrics are included.
+func (f *runtimeMetricsFilter) match(traceName string) bool {
+	matchAny := func(patterns []*regexp.Regexp) bool {
+		for _, re := range patterns {
+			if re.MatchString(traceName) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(f.include) > 0 && !matchAny(f.include) {
+		return false
+	}
+	return !matchAny(f.exclude)
+}
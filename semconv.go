@@ -0,0 +1,82 @@
+package flightrecorderreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/otel/semconv/v1.39.0/goconv"
+	"golang.org/x/exp/trace"
+)
+
+// semconvMetric describes a metric of the OTel semantic conventions.
+type semconvMetric interface {
+	Name() string
+	Unit() string
+	Description() string
+}
+
+// semconvMetrics maps the runtime/metrics metrics that the runtime records in
+// traces to the Go runtime metrics of the OTel semantic conventions with the
+// same meaning. The semantic conventions define both as UpDownCounters. The
+// only other metric in traces, /memory/classes/heap/objects:bytes, is just a
+// part of go.memory.used and has no equivalent.
+var semconvMetrics = map[string]semconvMetric{
+	"/gc/heap/goal:bytes":       goconv.MemoryGCGoal{},
+	"/sched/gomaxprocs:threads": goconv.ProcessorLimit{},
+}
+
+// metricInfo returns name, unit and description of a flight recorder metric.
+// If semconvNames is set, known metrics are renamed according to the OTel
+// semantic conventions. Other metrics keep the naming of runtime/metrics.
+func metricInfo(traceName string, semconvNames bool) (name, unit, description string) {
+	if sc, ok := semconvMetrics[traceName]; ok && semconvNames {
+		return sc.Name(), sc.Unit(), sc.Description()
+	}
+	name, unit = extractMetricNameUnit(traceName)
+	return name, unit, ""
+}
+
+// initUpDownCounter sets the type of metric to a non-monotonic cumulative sum,
+// which is how UpDownCounters of the semantic conventions are exported.
+func initUpDownCounter(metric pmetric.Metric) {
+	sum := metric.SetEmptySum()
+	sum.SetIsMonotonic(false)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+}
+
+// goroutineCount follows the number of goroutines that exist, which the
+// semantic conventions define as go.goroutine.count. The trace states the
+// state of every goroutine at its start, so the count is complete.
+type goroutineCount struct {
+	count int64
+}
+
+// handleTransition applies a state transition of a goroutine.
+func (c *goroutineCount) handleTransition(st trace.StateTransition) {
+	exists := func(state trace.GoState) bool {
+		return state != trace.GoNotExist && state != trace.GoUndetermined
+	}
+	from, to := st.Goroutine()
+	switch {
+	case !exists(from) && exists(to):
+		c.count++
+	case exists(from) && !exists(to):
+		c.count--
+	}
+}
+
+// appendTo appends go.goroutine.count with the number of goroutines at end to
+// metrics, like a metric reader of an instrumented program would observe it.
+func (c *goroutineCount) appendTo(metrics pmetric.MetricSlice, start, end time.Time) {
+	sc := goconv.GoroutineCount{}
+	metric := metrics.AppendEmpty()
+	metric.SetName(sc.Name())
+	metric.SetUnit(sc.Unit())
+	metric.SetDescription(sc.Description())
+	initUpDownCounter(metric)
+	dp := metric.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(end))
+	dp.SetIntValue(c.count)
+}
@@ -0,0 +1,83 @@
+package flightrecorderreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"golang.org/x/exp/trace"
+
+	"github.com/florianl/flightrecorderreceiver/internal/metadata"
+)
+
+// threadStats follows OS threads (Ms) and the Ps they run. Blocking system
+// calls, e.g. of cgo, make the runtime hand their P off to other threads and
+// start new threads, so these numbers rise before anything else.
+type threadStats struct {
+	// threads holds all threads that emitted events.
+	threads map[trace.ThreadID]bool
+	// procThreads maps P to the thread it ran on most recently.
+	procThreads map[trace.ProcID]trace.ThreadID
+	handoffs    int64
+
+	// syscalls is the number of goroutines, and thus threads, in system
+	// calls.
+	syscalls     int64
+	peakSyscalls int64
+}
+
+func newThreadStats() *threadStats {
+	return &threadStats{
+		threads:     make(map[trace.ThreadID]bool),
+		procThreads: make(map[trace.ProcID]trace.ThreadID),
+	}
+}
+
+// handleEvent handles any event.
+func (t *threadStats) handleEvent(ev trace.Event) {
+	thread := ev.Thread()
+	if ev.Kind() == trace.EventStateTransition {
+		t.handleTransition(ev.StateTransition(), thread)
+	}
+	if thread == trace.NoThread {
+		return
+	}
+	t.threads[thread] = true
+	if proc := ev.Proc(); proc != trace.NoProc {
+		t.procThreads[proc] = thread
+	}
+}
+
+// handleTransition handles a state transition on the given thread.
+func (t *threadStats) handleTransition(st trace.StateTransition, thread trace.ThreadID) {
+	switch st.Resource.Kind {
+	case trace.ResourceProc:
+		from, to := st.Proc()
+		if from != trace.ProcIdle || to != trace.ProcRunning || thread == trace.NoThread {
+			return
+		}
+		proc := st.Resource.Proc()
+		if last, ok := t.procThreads[proc]; ok && last != thread {
+			t.handoffs++
+		}
+		t.procThreads[proc] = thread
+	case trace.ResourceGoroutine:
+		from, to := st.Goroutine()
+		if from == trace.GoSyscall && t.syscalls > 0 {
+			t.syscalls--
+		}
+		if to == trace.GoSyscall {
+			t.syscalls++
+			t.peakSyscalls = max(t.peakSyscalls, t.syscalls)
+		}
+	}
+}
+
+// recordMetrics records the statistics covering [start, end] in mb.
+func (t *threadStats) recordMetrics(mb *metadata.MetricsBuilder, start, end time.Time) {
+	endTS := pcommon.NewTimestampFromTime(end)
+	mb.RecordGoThreadCountDataPoint(endTS, int64(len(t.threads)))
+	mb.RecordGoThreadSyscallPeakDataPoint(endTS, t.peakSyscalls)
+	if duration := end.Sub(start); duration > 0 {
+		mb.RecordGoProcHandoffRateDataPoint(endTS, float64(t.handoffs)/duration.Seconds())
+	}
+}
@@ -0,0 +1,133 @@
+package flightrecorderreceiver
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"golang.org/x/exp/trace"
+)
+
+// spanConverter turns runtime/trace tasks and regions into spans.
+//
+// Tasks become spans with their parent task as parent span. Regions become
+// spans on the goroutine they were started on, with the enclosing region or
+// otherwise the span of their task as parent. The spans are kept with the
+// tasks and regions of annotations.
+//
+// Consecutive flight records of a process overlap, so the same task or region
+// is converted many times. Its IDs are derived from the event it began with,
+// so that they are the same in every flight record, and spans that began at or
+// before the end of the previous flight record of the source are left out.
+type spanConverter struct {
+	spans       ptrace.SpanSlice
+	annotations *userAnnotations
+	history     sourceHistory
+}
+
+func newSpanConverter(spans ptrace.SpanSlice, annotations *userAnnotations, history sourceHistory) *spanConverter {
+	return &spanConverter{
+		spans:       spans,
+		annotations: annotations,
+		history:     history,
+	}
+}
+
+// newSpan creates a span for the task or region that began with ev at ts. If
+// parent is not empty, the span becomes its child. Otherwise the span is the
+// root of a new trace.
+func (c *spanConverter) newSpan(name string, parent ptrace.Span, ev trace.Event, ts time.Time) ptrace.Span {
+	traceID, spanID := spanIdentity(ev, name)
+	span := c.spans.AppendEmpty()
+	span.SetName(name)
+	span.SetKind(ptrace.SpanKindInternal)
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(ts))
+	span.SetSpanID(spanID)
+	if parent != (ptrace.Span{}) {
+		span.SetTraceID(parent.TraceID())
+		span.SetParentSpanID(parent.SpanID())
+	} else {
+		span.SetTraceID(traceID)
+	}
+	if goID := ev.Goroutine(); goID != trace.NoGoroutine {
+		span.Attributes().PutInt(attrGoID, int64(goID))
+	}
+	return span
+}
+
+// beginTask creates the span of a task that began with ev.
+func (c *spanConverter) beginTask(task *annotatedTask, ev trace.Event, ts time.Time) {
+	var parent ptrace.Span
+	if task.parent != nil {
+		parent = task.parent.span
+	}
+	task.span = c.newSpan(task.typ, parent, ev, ts)
+	task.span.Attributes().PutInt(attrTaskID, int64(ev.Task().ID))
+}
+
+// endTask ends the span of a task.
+func (c *spanConverter) endTask(task *annotatedTask, ts time.Time) {
+	task.span.SetEndTimestamp(pcommon.NewTimestampFromTime(ts))
+}
+
+// beginRegion creates the span of a region that began with ev. The region is
+// the innermost active region of its goroutine.
+func (c *spanConverter) beginRegion(region *annotatedRegion, ev trace.Event, ts time.Time) {
+	goID := ev.Goroutine()
+	var parent ptrace.Span
+	if task, ok := c.annotations.tasks[region.region.Task]; ok {
+		parent = task.span
+	}
+	if active := c.annotations.regions[goID]; len(active) > 1 {
+		parent = active[len(active)-2].span
+	}
+	region.span = c.newSpan(region.region.Type, parent, ev, ts)
+	if region.region.Task != trace.BackgroundTask {
+		region.span.Attributes().PutInt(attrTaskID, int64(region.region.Task))
+	}
+}
+
+// endRegion ends the span of a region.
+func (c *spanConverter) endRegion(region *annotatedRegion, ts time.Time) {
+	region.span.SetEndTimestamp(pcommon.NewTimestampFromTime(ts))
+}
+
+// finish ends all tasks and regions that are still active at ts and drops the
+// spans that were emitted for an earlier flight record.
+func (c *spanConverter) finish(ts time.Time) {
+	end := pcommon.NewTimestampFromTime(ts)
+	for _, task := range c.annotations.tasks {
+		if task.open {
+			task.span.SetEndTimestamp(end)
+		}
+	}
+	for _, active := range c.annotations.regions {
+		for _, region := range active {
+			region.span.SetEndTimestamp(end)
+		}
+	}
+	c.spans.RemoveIf(func(span ptrace.Span) bool {
+		return c.history.emitted(span.StartTimestamp().AsTime())
+	})
+}
+
+// spanIdentity derives the IDs of the span of the task or region with the
+// given name that began with ev. The time of ev is the monotonic time of the
+// process, which is the same in all flight records.
+func spanIdentity(ev trace.Event, name string) (pcommon.TraceID, pcommon.SpanID) {
+	h := fnv.New128a()
+	var buf [8]byte
+	for _, v := range []uint64{uint64(ev.Kind()), uint64(ev.Time()), uint64(ev.Goroutine())} {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		_, _ = h.Write(buf[:])
+	}
+	_, _ = h.Write([]byte(name))
+
+	var traceID pcommon.TraceID
+	h.Sum(traceID[:0])
+	var spanID pcommon.SpanID
+	copy(spanID[:], traceID[8:])
+	return traceID, spanID
+}
@@ -0,0 +1,205 @@
+package flightrecorderreceiver
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"golang.org/x/exp/trace"
+
+	"github.com/florianl/flightrecorderreceiver/internal/metadata"
+)
+
+// gomaxprocsMetric is the name of the runtime/metrics metric holding
+// GOMAXPROCS.
+const gomaxprocsMetric = "/sched/gomaxprocs:threads"
+
+// utilizationChange is a change of the mutator utilization at ts.
+type utilizationChange struct {
+	ts   time.Time
+	util float64
+}
+
+// runningGoroutine is a goroutine running on a P since a point in time.
+type runningGoroutine struct {
+	goID  trace.GoID
+	since time.Time
+}
+
+// procUtilization tracks how busy each P is and how much of the Ps is
+// available to the application rather than the GC, i.e. the mutator
+// utilization that go tool trace uses for its MMU curve.
+//
+// Ps are unavailable to the application while the world is stopped, while
+// they run GC mark workers and while the goroutine they run assists the GC.
+type procUtilization struct {
+	// isGCWorker reports whether a goroutine is a GC mark worker.
+	isGCWorker func(trace.GoID) bool
+
+	// gomaxprocs is the most recent value of GOMAXPROCS. It is zero until
+	// the trace reports it.
+	gomaxprocs int
+	// procs holds all Ps that ran goroutines.
+	procs map[trace.ProcID]bool
+	// running maps P to the goroutine it runs.
+	running map[trace.ProcID]runningGoroutine
+	// procOf maps running goroutines to their P.
+	procOf map[trace.GoID]trace.ProcID
+	// busy maps P to the time it ran goroutines.
+	busy map[trace.ProcID]time.Duration
+	// assists holds goroutines that assist the GC.
+	assists map[trace.GoID]bool
+	// stw is the number of active stop-the-world ranges.
+	stw int
+
+	// changes is the mutator utilization as a step function.
+	changes []utilizationChange
+}
+
+func newProcUtilization(isGCWorker func(trace.GoID) bool) *procUtilization {
+	return &procUtilization{
+		isGCWorker: isGCWorker,
+		procs:      make(map[trace.ProcID]bool),
+		running:    make(map[trace.ProcID]runningGoroutine),
+		procOf:     make(map[trace.GoID]trace.ProcID),
+		busy:       make(map[trace.ProcID]time.Duration),
+		assists:    make(map[trace.GoID]bool),
+	}
+}
+
+// handleMetric handles EventMetric.
+func (u *procUtilization) handleMetric(m trace.Metric, ts time.Time) {
+	if m.Name != gomaxprocsMetric {
+		return
+	}
+	u.gomaxprocs = int(m.Value.Uint64())
+	u.update(ts)
+}
+
+// handleTransition handles a state transition of a goroutine at ts.
+func (u *procUtilization) handleTransition(ev trace.Event, st trace.StateTransition, ts time.Time) {
+	goID := st.Resource.Goroutine()
+	from, to := st.Goroutine()
+	if from == trace.GoRunning {
+		if proc, ok := u.procOf[goID]; ok {
+			u.busy[proc] += ts.Sub(u.running[proc].since)
+			delete(u.running, proc)
+			delete(u.procOf, goID)
+		}
+	}
+	if to == trace.GoRunning {
+		if proc := ev.Proc(); proc != trace.NoProc {
+			u.procs[proc] = true
+			u.running[proc] = runningGoroutine{goID: goID, since: ts}
+			u.procOf[goID] = proc
+		}
+	}
+	u.update(ts)
+}
+
+// handleRange handles EventRangeBegin and EventRangeEnd of stop-the-world
+// pauses and mark assists at ts.
+func (u *procUtilization) handleRange(ev trace.Event, ts time.Time) {
+	r := ev.Range()
+	begin := ev.Kind() == trace.EventRangeBegin
+	switch {
+	case r.Name == rangeGCMarkAssist && r.Scope.Kind == trace.ResourceGoroutine:
+		if begin {
+			u.assists[r.Scope.Goroutine()] = true
+		} else {
+			delete(u.assists, r.Scope.Goroutine())
+		}
+	default:
+		if _, ok := stwReason(r.Name); !ok {
+			return
+		}
+		if begin {
+			u.stw++
+		} else if u.stw > 0 {
+			u.stw--
+		}
+	}
+	u.update(ts)
+}
+
+// update records the mutator utilization at ts. It needs to be called on every
+// event that might change it, e.g. a GC mark worker getting its label.
+func (u *procUtilization) update(ts time.Time) {
+	util := 0.0
+	if u.stw == 0 {
+		procs := max(u.gomaxprocs, len(u.procs), 1)
+		gc := 0
+		for _, g := range u.running {
+			if u.isGCWorker(g.goID) || u.assists[g.goID] {
+				gc++
+			}
+		}
+		util = max(1-float64(gc)/float64(procs), 0)
+	}
+	if n := len(u.changes); n > 0 && u.changes[n-1].util == util {
+		return
+	}
+	u.changes = append(u.changes, utilizationChange{ts: ts, util: util})
+}
+
+// mmu returns the minimum mutator utilization of all windows of the given
+// size within [start, end]. It returns false if the window does not fit.
+func (u *procUtilization) mmu(window time.Duration, start, end time.Time) (float64, bool) {
+	if window <= 0 || end.Sub(start) < window {
+		return 0, false
+	}
+	// The application has all Ps before the first change.
+	changes := append([]utilizationChange{{ts: start, util: 1}}, u.changes...)
+	// integrals holds the integral of the utilization from start to the
+	// time of each change, in nanoseconds.
+	integrals := make([]float64, len(changes))
+	for i := 1; i < len(changes); i++ {
+		integrals[i] = integrals[i-1] + changes[i-1].util*float64(changes[i].ts.Sub(changes[i-1].ts))
+	}
+	integral := func(ts time.Time) float64 {
+		i := sort.Search(len(changes), func(i int) bool { return changes[i].ts.After(ts) }) - 1
+		return integrals[i] + changes[i].util*float64(ts.Sub(changes[i].ts))
+	}
+
+	// The utilization is a step function, so the window with the minimum
+	// utilization starts or ends at a change.
+	minUtil := 1.0
+	last := end.Add(-window)
+	for _, c := range changes {
+		for _, windowStart := range []time.Time{c.ts, c.ts.Add(-window)} {
+			if windowStart.Before(start) || windowStart.After(last) {
+				continue
+			}
+			util := (integral(windowStart.Add(window)) - integral(windowStart)) / float64(window)
+			minUtil = min(minUtil, util)
+		}
+	}
+	return max(minUtil, 0), true
+}
+
+// recordMetrics accounts the Ps that still run goroutines until end and
+// records the utilization of each P and the MMU of each window covering
+// [start, end] in mb.
+func (u *procUtilization) recordMetrics(mb *metadata.MetricsBuilder, windows []time.Duration, start, end time.Time) {
+	for proc, g := range u.running {
+		u.busy[proc] += end.Sub(g.since)
+		u.running[proc] = runningGoroutine{goID: g.goID, since: end}
+	}
+	endTS := pcommon.NewTimestampFromTime(end)
+
+	if duration := end.Sub(start); duration > 0 {
+		for _, proc := range slices.Sorted(maps.Keys(u.procs)) {
+			mb.RecordGoProcUtilizationDataPoint(endTS, float64(u.busy[proc])/float64(duration), int64(proc))
+		}
+	}
+
+	for _, window := range windows {
+		util, ok := u.mmu(window, start, end)
+		if !ok {
+			continue
+		}
+		mb.RecordGoGcMmuDataPoint(endTS, util, window.String())
+	}
+}